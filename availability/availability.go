@@ -0,0 +1,266 @@
+// Package availability finds free appointment slots in civil time.
+//
+// Slots are found within working hours, such as a civil.OpeningHours, and
+// avoid busy intervals, such as existing appointments. Busy intervals are
+// held in a civil.IntervalIndex, so that thousands of them can be checked
+// efficiently.
+package availability
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+var (
+	errBadLength = errors.New("availability: slot length is not a positive whole number of seconds")
+	errBadAlign  = errors.New("availability: slot alignment is not a positive whole number of seconds")
+)
+
+// WorkingHours returns the times that are available for appointments on the
+// dates in a range. It is implemented by *civil.OpeningHours.
+type WorkingHours interface {
+	OpenIntervals(r civil.DateRange) []civil.DateTimeRange
+}
+
+// Options control the slots that are found.
+type Options struct {
+	// Length is the length of each slot, which must be a whole number
+	// of seconds.
+	Length time.Duration
+
+	// Align is the interval between slot start times, which are aligned to
+	// multiples of Align after midnight. For example, with an Align of 15
+	// minutes slots start on the hour and at 15, 30 and 45 minutes past.
+	// It must be a whole number of seconds. If zero, Length is used.
+	Align time.Duration
+
+	// Buffer is the minimum time between a slot and a busy interval, such
+	// as time to travel between appointments.
+	Buffer time.Duration
+}
+
+// Finder finds free slots within working hours that do not overlap busy
+// intervals.
+//
+// A Finder is immutable, and is safe for concurrent use by multiple
+// goroutines provided its working hours are.
+type Finder struct {
+	hours WorkingHours
+	busy  *civil.IntervalIndex
+}
+
+// New returns a finder for the working hours and busy intervals. If hours
+// is nil, all times are working hours.
+func New(hours WorkingHours, busy []civil.DateTimeRange) *Finder {
+	return &Finder{hours: hours, busy: civil.NewIntervalIndex(intervalsOf(busy))}
+}
+
+// WithBusy returns a finder that avoids the additional busy intervals as
+// well as those of f, such as when an appointment has just been booked.
+// The original finder is unchanged.
+func (f *Finder) WithBusy(busy ...civil.DateTimeRange) *Finder {
+	return &Finder{hours: f.hours, busy: f.busy.With(intervalsOf(busy)...)}
+}
+
+// intervalsOf returns the non-empty ranges as intervals.
+func intervalsOf(ranges []civil.DateTimeRange) []civil.Interval {
+	intervals := make([]civil.Interval, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			intervals = append(intervals, civil.Interval{Range: r})
+		}
+	}
+	return intervals
+}
+
+// Slots returns an iterator over the free slots within r, in order.
+func (f *Finder) Slots(r civil.DateTimeRange, opts Options) (*Iterator, error) {
+	// date-times have a resolution of one second
+	if !isWholeSeconds(opts.Length) {
+		return nil, errBadLength
+	}
+	if opts.Align == 0 {
+		opts.Align = opts.Length
+	}
+	if !isWholeSeconds(opts.Align) {
+		return nil, errBadAlign
+	}
+	if opts.Buffer < 0 {
+		opts.Buffer = 0
+	}
+	it := &Iterator{
+		finder: f,
+		opts:   opts,
+		window: r,
+		date:   dateOf(r.Start),
+	}
+	if r.IsEmpty() {
+		it.done = true
+	}
+	return it, nil
+}
+
+// All returns the free slots within r, in order.
+func (f *Finder) All(r civil.DateTimeRange, opts Options) ([]civil.DateTimeRange, error) {
+	it, err := f.Slots(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	var slots []civil.DateTimeRange
+	for {
+		slot, ok := it.Next()
+		if !ok {
+			return slots, nil
+		}
+		slots = append(slots, slot)
+	}
+}
+
+// Iterator iterates lazily over free slots. Working hours are read a day at a
+// time, so an iterator over a long range does not do more work than needed.
+type Iterator struct {
+	finder *Finder
+	opts   Options
+	window civil.DateTimeRange
+	date   civil.Date // next date to read working hours for
+	done   bool       // no more working hours to read
+
+	open    []civil.DateTimeRange // working hours that have been read
+	free    []civil.DateTimeRange // free times within the current working hours
+	current civil.DateTime        // start of the next slot in free[0]
+}
+
+// Next returns the next free slot, and false if there are no more.
+func (it *Iterator) Next() (civil.DateTimeRange, bool) {
+	for {
+		for len(it.free) > 0 {
+			free := it.free[0]
+			start := it.current
+			if start.Before(free.Start) {
+				start = align(free.Start, it.opts.Align)
+			}
+			slot := civil.DateTimeRange{Start: start, End: start.Add(it.opts.Length)}
+			if slot.End.After(free.End) {
+				it.free = it.free[1:]
+				continue
+			}
+			it.current = start.Add(it.opts.Align)
+			return slot, true
+		}
+		open, ok := it.nextOpen()
+		if !ok {
+			return civil.DateTimeRange{}, false
+		}
+		it.free = it.subtractBusy(open)
+	}
+}
+
+// nextOpen returns the next period of working hours within the window.
+// Periods that continue past midnight are combined with the following day,
+// so that slots can span midnight.
+//
+// Working hours that are continuous for days at a time, such as when all
+// times are working hours, are returned in parts of about a day, so that
+// the first slot is found without reading the whole window. Each part
+// overlaps the next by the slot length, so that slots can span the cut.
+// Slots in the overlap are not repeated, because Next starts each slot at
+// or after it.current.
+func (it *Iterator) nextOpen() (civil.DateTimeRange, bool) {
+	for !it.done {
+		// the first period is complete if another period follows it,
+		// or if it ends before the next day to be read
+		n := len(it.open)
+		if n > 1 || n == 1 && it.open[0].End.Before(midnight(it.date)) {
+			break
+		}
+		if n == 1 && it.open[0].End.Sub(it.open[0].Start) > it.opts.Length+24*time.Hour {
+			part := civil.DateTimeRange{Start: it.open[0].Start, End: it.open[0].Start.Add(it.opts.Length + 24*time.Hour)}
+			it.open[0].Start = part.End.Add(-it.opts.Length)
+			return part, true
+		}
+		it.readDay()
+	}
+	if len(it.open) == 0 {
+		return civil.DateTimeRange{}, false
+	}
+	open := it.open[0]
+	it.open = it.open[1:]
+	return open, true
+}
+
+// readDay reads the working hours on it.date, clipped to the window,
+// and merges them with the working hours already read.
+func (it *Iterator) readDay() {
+	day := civil.DateTimeRange{Start: midnight(it.date), End: midnight(it.date.AddDate(0, 0, 1))}
+	var periods []civil.DateTimeRange
+	if it.finder.hours == nil {
+		periods = []civil.DateTimeRange{day}
+	} else {
+		periods = it.finder.hours.OpenIntervals(civil.DateRangeFor(it.date, it.date))
+	}
+	for _, p := range periods {
+		if p = p.Intersect(day).Intersect(it.window); p.IsEmpty() {
+			continue
+		}
+		if n := len(it.open); n > 0 && !p.Start.After(it.open[n-1].End) {
+			if p.End.After(it.open[n-1].End) {
+				it.open[n-1].End = p.End
+			}
+			continue
+		}
+		it.open = append(it.open, p)
+	}
+	it.date = it.date.AddDate(0, 0, 1)
+	if !midnight(it.date).Before(it.window.End) {
+		it.done = true
+	}
+}
+
+// subtractBusy returns the parts of open that are not within the buffer of
+// a busy interval.
+func (it *Iterator) subtractBusy(open civil.DateTimeRange) []civil.DateTimeRange {
+	buffer := it.opts.Buffer
+	query := civil.DateTimeRange{Start: open.Start.Add(-buffer), End: open.End.Add(buffer)}
+	var free []civil.DateTimeRange
+	start := open.Start
+	// results are in order of start
+	for _, busy := range it.finder.busy.Overlapping(query) {
+		busyStart, busyEnd := busy.Range.Start.Add(-buffer), busy.Range.End.Add(buffer)
+		if busyStart.After(start) {
+			free = append(free, civil.DateTimeRange{Start: start, End: busyStart})
+		}
+		if busyEnd.After(start) {
+			start = busyEnd
+		}
+	}
+	if open.End.After(start) {
+		free = append(free, civil.DateTimeRange{Start: start, End: open.End})
+	}
+	return free
+}
+
+// isWholeSeconds reports whether d is a positive whole number of seconds.
+func isWholeSeconds(d time.Duration) bool {
+	return d >= time.Second && d%time.Second == 0
+}
+
+// align returns the first date-time at or after dt that is a multiple
+// of d after midnight.
+func align(dt civil.DateTime, d time.Duration) civil.DateTime {
+	since := dt.Sub(midnight(dateOf(dt)))
+	if rem := since % d; rem != 0 {
+		dt = dt.Add(d - rem)
+	}
+	return dt
+}
+
+func midnight(d civil.Date) civil.DateTime {
+	year, month, day := d.Date()
+	return civil.DateTimeFor(year, month, day, 0, 0, 0)
+}
+
+func dateOf(dt civil.DateTime) civil.Date {
+	return civil.DateFor(dt.Date())
+}
@@ -0,0 +1,163 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseDateTime(s string) civil.DateTime {
+	dt, err := civil.ParseDateTime(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return dt
+}
+
+func rangeOf(start, end string) civil.DateTimeRange {
+	return civil.DateTimeRange{Start: mustParseDateTime(start), End: mustParseDateTime(end)}
+}
+
+func slotStrings(slots []civil.DateTimeRange) []string {
+	var s []string
+	for _, slot := range slots {
+		s = append(s, slot.String())
+	}
+	return s
+}
+
+func TestSlots(t *testing.T) {
+	hours, err := civil.ParseOpeningHours("Mo-Fr 09:00-12:00,13:00-17:00")
+	require.NoError(t, err)
+	f := New(hours, []civil.DateTimeRange{
+		rangeOf("2021-03-01T09:00", "2021-03-01T10:00"),
+		rangeOf("2021-03-01T10:20", "2021-03-01T10:40"),
+		rangeOf("2021-03-01T13:00", "2021-03-01T16:00"),
+		rangeOf("2021-03-01T15:00", "2021-03-01T16:45"),
+	})
+	slots, err := f.All(rangeOf("2021-03-01T00:00", "2021-03-02T10:00"), Options{
+		Length: 30 * time.Minute,
+		Align:  15 * time.Minute,
+		Buffer: 10 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2021-03-01T11:00:00/2021-03-01T11:30:00",
+		"2021-03-01T11:15:00/2021-03-01T11:45:00",
+		"2021-03-01T11:30:00/2021-03-01T12:00:00",
+		"2021-03-02T09:00:00/2021-03-02T09:30:00",
+		"2021-03-02T09:15:00/2021-03-02T09:45:00",
+		"2021-03-02T09:30:00/2021-03-02T10:00:00",
+	}, slotStrings(slots))
+
+	// a booked slot is avoided
+	booked := f.WithBusy(slots[0])
+	it, err := booked.Slots(rangeOf("2021-03-01T00:00", "2021-03-02T00:00"), Options{Length: 30 * time.Minute})
+	require.NoError(t, err)
+	slot, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "2021-03-01T11:30:00/2021-03-01T12:00:00", slot.String())
+	_, ok = it.Next()
+	assert.False(t, ok)
+}
+
+func TestSlotsOvernight(t *testing.T) {
+	hours, err := civil.ParseOpeningHours("Fr 22:00-02:00")
+	require.NoError(t, err)
+	f := New(hours, nil)
+	slots, err := f.All(rangeOf("2021-03-05T00:00", "2021-03-07T00:00"), Options{Length: time.Hour, Align: 30 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"2021-03-05T22:00:00/2021-03-05T23:00:00",
+		"2021-03-05T22:30:00/2021-03-05T23:30:00",
+		"2021-03-05T23:00:00/2021-03-06T00:00:00",
+		"2021-03-05T23:30:00/2021-03-06T00:30:00",
+		"2021-03-06T00:00:00/2021-03-06T01:00:00",
+		"2021-03-06T00:30:00/2021-03-06T01:30:00",
+		"2021-03-06T01:00:00/2021-03-06T02:00:00",
+	}, slotStrings(slots))
+
+	// the window cuts off working hours
+	slots, err = f.All(rangeOf("2021-03-05T22:10", "2021-03-06T00:00"), Options{Length: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2021-03-05T23:00:00/2021-03-06T00:00:00"}, slotStrings(slots))
+}
+
+func TestSlotsManyBusy(t *testing.T) {
+	// no working hours, and a 50 minute appointment every hour for a year
+	var busy []civil.DateTimeRange
+	start := mustParseDateTime("2021-01-01T00:00")
+	for i := 0; i < 24*365; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+		busy = append(busy, civil.DateTimeRange{Start: at, End: at.Add(50 * time.Minute)})
+	}
+	f := New(nil, busy)
+	it, err := f.Slots(rangeOf("2021-01-01T00:00", "2022-01-01T00:00"), Options{Length: 10 * time.Minute, Align: 5 * time.Minute})
+	require.NoError(t, err)
+	count := 0
+	for {
+		slot, ok := it.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, 50, slot.Start.Minute())
+		count++
+	}
+	assert.Equal(t, 24*365, count)
+
+	// a buffer leaves no room
+	slots, err := f.All(rangeOf("2021-01-01T00:00", "2021-02-01T00:00"), Options{Length: 10 * time.Minute, Buffer: time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+func TestSlotsContinuous(t *testing.T) {
+	// the first slot is found without reading the whole window
+	f := New(nil, []civil.DateTimeRange{rangeOf("2021-01-01T00:00", "2021-01-01T09:00")})
+	it, err := f.Slots(rangeOf("2021-01-01T00:00", "2400-01-01T00:00"), Options{Length: time.Hour})
+	require.NoError(t, err)
+	slot, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "2021-01-01T09:00:00/2021-01-01T10:00:00", slot.String())
+	assert.True(t, it.date.Before(civil.DateFor(2021, 1, 5)), "read to %s", it.date)
+
+	// slots span the parts that continuous working hours are read in
+	always, err := civil.ParseOpeningHours("24/7")
+	require.NoError(t, err)
+	busy := rangeOf("2021-01-03T00:10", "2021-01-03T00:40")
+	slots, err := New(always, []civil.DateTimeRange{busy}).All(rangeOf("2021-01-01T00:00", "2021-01-11T00:00"),
+		Options{Length: 90 * time.Minute, Align: 30 * time.Minute})
+	require.NoError(t, err)
+	next := mustParseDateTime("2021-01-01T00:00")
+	for _, slot := range slots {
+		if next.Before(busy.End) && slot.Start.After(next) {
+			// the slots that overlap the busy interval are skipped
+			next = mustParseDateTime("2021-01-03T01:00")
+		}
+		assert.Equal(t, next, slot.Start)
+		next = next.Add(30 * time.Minute)
+	}
+	assert.Len(t, slots, 478-4)
+}
+
+func TestSlotsErrors(t *testing.T) {
+	f := New(nil, nil)
+	day := rangeOf("2021-01-01T00:00", "2021-01-02T00:00")
+	for _, opts := range []Options{
+		{},
+		{Length: -time.Hour},
+		{Length: 500 * time.Millisecond},
+		{Length: time.Minute + time.Millisecond},
+		{Length: time.Hour, Align: 500 * time.Millisecond},
+		{Length: time.Hour, Align: -time.Minute},
+	} {
+		_, err := f.Slots(day, opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+	slots, err := f.All(rangeOf("2021-01-02T00:00", "2021-01-01T00:00"), Options{Length: time.Hour})
+	assert.NoError(t, err)
+	assert.Empty(t, slots)
+}
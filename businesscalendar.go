@@ -0,0 +1,223 @@
+package civil
+
+import "fmt"
+
+// maxBusinessDaySearch is the number of days searched for a business day
+// before giving up, which only happens with a calendar that is closed
+// for years at a time.
+const maxBusinessDaySearch = 3660
+
+// HolidaySource reports whether a date is a holiday.
+//
+// Implementations used by a BusinessCalendar must be safe for concurrent
+// use by multiple goroutines.
+type HolidaySource interface {
+	IsHoliday(d Date) bool
+}
+
+// HolidayFunc is an adapter that allows a function to be used as a
+// HolidaySource.
+type HolidayFunc func(d Date) bool
+
+// IsHoliday returns f(d).
+func (f HolidayFunc) IsHoliday(d Date) bool {
+	return f(d)
+}
+
+// holidayDates is a HolidaySource for a fixed list of dates.
+type holidayDates struct {
+	set *DateSet
+}
+
+// HolidayDates returns a HolidaySource containing the dates.
+func HolidayDates(dates ...Date) HolidaySource {
+	return holidayDates{set: NewDateSet(dates...)}
+}
+
+func (h holidayDates) IsHoliday(d Date) bool {
+	return h.set.Has(d)
+}
+
+// BusinessDayConvention determines how BusinessCalendar.Adjust moves a
+// date that is not a business day.
+type BusinessDayConvention int
+
+const (
+	// Unadjusted leaves the date as it is.
+	Unadjusted BusinessDayConvention = iota
+
+	// Following moves the date forward to the next business day.
+	Following
+
+	// ModifiedFollowing moves the date forward to the next business day,
+	// unless that is in the next month, in which case the date is moved
+	// back to the previous business day.
+	ModifiedFollowing
+
+	// Preceding moves the date back to the previous business day.
+	Preceding
+
+	// ModifiedPreceding moves the date back to the previous business day,
+	// unless that is in the previous month, in which case the date is moved
+	// forward to the next business day.
+	ModifiedPreceding
+)
+
+var businessDayConventionNames = []string{
+	Unadjusted:        "unadjusted",
+	Following:         "following",
+	ModifiedFollowing: "modified-following",
+	Preceding:         "preceding",
+	ModifiedPreceding: "modified-preceding",
+}
+
+// String returns the name of the convention, such as "modified-following".
+func (c BusinessDayConvention) String() string {
+	if c >= 0 && int(c) < len(businessDayConventionNames) {
+		return businessDayConventionNames[c]
+	}
+	return fmt.Sprintf("BusinessDayConvention(%d)", int(c))
+}
+
+// BusinessCalendar determines which dates are business days. A date is a
+// business day if it is not on the weekend and is not a holiday.
+//
+// A BusinessCalendar is immutable, and is safe for concurrent use by multiple
+// goroutines provided its holiday sources are.
+//
+// The methods that search for a business day give up after ten years,
+// and return the zero Date if none is found.
+type BusinessCalendar struct {
+	weekend  Weekdays
+	holidays []HolidaySource
+}
+
+// NewBusinessCalendar returns a calendar with the given weekend and holidays.
+// Use Weekend for Saturday and Sunday, WeekdaysOf(time.Friday, time.Saturday)
+// for Friday and Saturday, and so on. Any of the holiday sources may be nil.
+func NewBusinessCalendar(weekend Weekdays, holidays ...HolidaySource) *BusinessCalendar {
+	c := &BusinessCalendar{weekend: weekend}
+	for _, h := range holidays {
+		if h != nil {
+			c.holidays = append(c.holidays, h)
+		}
+	}
+	return c
+}
+
+// JointBusinessCalendar returns a calendar where a date is a business day
+// only if it is a business day in every one of the calendars. This is
+// used, for example, for a payment that needs banks in two countries to
+// be open. Any of the calendars may be nil.
+func JointBusinessCalendar(calendars ...*BusinessCalendar) *BusinessCalendar {
+	c := &BusinessCalendar{}
+	for _, cal := range calendars {
+		if cal == nil {
+			continue
+		}
+		c.weekend |= cal.weekend
+		c.holidays = append(c.holidays, cal.holidays...)
+	}
+	return c
+}
+
+// Weekend returns the days of the week that are not business days.
+func (c *BusinessCalendar) Weekend() Weekdays {
+	return c.weekend
+}
+
+// IsBusinessDay reports whether d is a business day.
+func (c *BusinessCalendar) IsBusinessDay(d Date) bool {
+	if c.weekend.Has(d.Weekday()) {
+		return false
+	}
+	for _, h := range c.holidays {
+		if h.IsHoliday(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextBusinessDay returns the first business day after d.
+func (c *BusinessCalendar) NextBusinessDay(d Date) Date {
+	return c.search(d.AddDate(0, 0, 1), 1)
+}
+
+// PrevBusinessDay returns the last business day before d.
+func (c *BusinessCalendar) PrevBusinessDay(d Date) Date {
+	return c.search(d.AddDate(0, 0, -1), -1)
+}
+
+// AddBusinessDays returns the date that is n business days after d, or
+// before d if n is negative. If n is zero, the result is d if it is a
+// business day, or the next business day if it is not.
+func (c *BusinessCalendar) AddBusinessDays(d Date, n int) Date {
+	if n == 0 {
+		return c.search(d, 1)
+	}
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for i := 0; i < n; i++ {
+		d = c.search(d.AddDate(0, 0, step), step)
+		if d.IsZero() {
+			break
+		}
+	}
+	return d
+}
+
+// BusinessDaysBetween returns the number of business days from start up to,
+// but not including, end. If end is before start, the result is negative.
+func (c *BusinessCalendar) BusinessDaysBetween(start, end Date) int {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	count := 0
+	for n, last := dayNumber(start), dayNumber(end); n < last; n++ {
+		if c.IsBusinessDay(dateForDayNumber(n)) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// Adjust returns d adjusted to a business day using the convention.
+func (c *BusinessCalendar) Adjust(d Date, convention BusinessDayConvention) Date {
+	switch convention {
+	case Following:
+		return c.search(d, 1)
+	case ModifiedFollowing:
+		if a := c.search(d, 1); a.Month() == d.Month() {
+			return a
+		}
+		return c.search(d, -1)
+	case Preceding:
+		return c.search(d, -1)
+	case ModifiedPreceding:
+		if a := c.search(d, -1); a.Month() == d.Month() {
+			return a
+		}
+		return c.search(d, 1)
+	}
+	return d
+}
+
+// search returns the first business day on or after d, if step is 1,
+// or on or before d, if step is -1.
+func (c *BusinessCalendar) search(d Date, step int) Date {
+	if c.weekend&AllWeekdays == AllWeekdays {
+		return Date{}
+	}
+	n := dayNumber(d)
+	for i := 0; i < maxBusinessDaySearch; i++ {
+		if d := dateForDayNumber(n); c.IsBusinessDay(d) {
+			return d
+		}
+		n += int64(step)
+	}
+	return Date{}
+}
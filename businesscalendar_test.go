@@ -0,0 +1,99 @@
+package civil
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessCalendar(t *testing.T) {
+	assert := assert.New(t)
+	d := mustParseDate
+	// Christmas Day and Boxing Day 2020 are Friday and Saturday,
+	// and the Boxing Day holiday is observed on Monday 28 December.
+	cal := NewBusinessCalendar(Weekend, HolidayDates(d("2020-12-25"), d("2020-12-28"), d("2021-01-01")), nil)
+	assert.Equal(Weekend, cal.Weekend())
+	assert.True(cal.IsBusinessDay(d("2020-12-24")))
+	assert.False(cal.IsBusinessDay(d("2020-12-25")))
+	assert.False(cal.IsBusinessDay(d("2020-12-26")))
+	assert.False(cal.IsBusinessDay(d("2020-12-28")))
+
+	assert.Equal(d("2020-12-29"), cal.NextBusinessDay(d("2020-12-24")))
+	assert.Equal(d("2020-12-24"), cal.PrevBusinessDay(d("2020-12-29")))
+
+	assert.Equal(d("2020-12-31"), cal.AddBusinessDays(d("2020-12-24"), 3))
+	assert.Equal(d("2021-01-04"), cal.AddBusinessDays(d("2020-12-25"), 4))
+	assert.Equal(d("2020-12-23"), cal.AddBusinessDays(d("2020-12-29"), -2))
+	assert.Equal(d("2020-12-24"), cal.AddBusinessDays(d("2020-12-24"), 0))
+	assert.Equal(d("2020-12-29"), cal.AddBusinessDays(d("2020-12-26"), 0))
+
+	assert.Equal(4, cal.BusinessDaysBetween(d("2020-12-24"), d("2021-01-04")))
+	assert.Equal(-4, cal.BusinessDaysBetween(d("2021-01-04"), d("2020-12-24")))
+	assert.Equal(0, cal.BusinessDaysBetween(d("2020-12-24"), d("2020-12-24")))
+}
+
+func TestBusinessCalendarAdjust(t *testing.T) {
+	d := mustParseDate
+	cal := NewBusinessCalendar(Weekend)
+	testCases := []struct {
+		Date       string
+		Convention BusinessDayConvention
+		Expected   string
+	}{
+		{"2021-07-31", Unadjusted, "2021-07-31"},
+		{"2021-07-31", Following, "2021-08-02"},
+		{"2021-07-31", ModifiedFollowing, "2021-07-30"},
+		{"2021-07-17", ModifiedFollowing, "2021-07-19"},
+		{"2021-05-01", Preceding, "2021-04-30"},
+		{"2021-05-01", ModifiedPreceding, "2021-05-03"},
+		{"2021-05-15", ModifiedPreceding, "2021-05-14"},
+		{"2021-05-14", ModifiedPreceding, "2021-05-14"},
+	}
+	for _, tc := range testCases {
+		actual := cal.Adjust(d(tc.Date), tc.Convention)
+		assert.Equal(t, tc.Expected, actual.String(), "%s %s", tc.Date, tc.Convention)
+	}
+	assert.Equal(t, "modified-following", ModifiedFollowing.String())
+	assert.Equal(t, "BusinessDayConvention(9)", BusinessDayConvention(9).String())
+}
+
+func TestJointBusinessCalendar(t *testing.T) {
+	assert := assert.New(t)
+	// Sunday to Thursday working week, with a holiday on Sunday 2 May
+	gulf := NewBusinessCalendar(WeekdaysOf(time.Friday, time.Saturday), HolidayDates(DateFor(2021, 5, 2)))
+	// Monday to Friday, with a holiday on Monday 3 May
+	uk := NewBusinessCalendar(Weekend, HolidayFunc(func(d Date) bool {
+		return d.Equal(DateFor(2021, 5, 3))
+	}))
+	joint := JointBusinessCalendar(gulf, uk)
+	assert.Equal(WeekdaysOf(time.Friday, time.Saturday, time.Sunday), joint.Weekend())
+	assert.True(gulf.IsBusinessDay(DateFor(2021, 5, 3)))
+	assert.False(joint.IsBusinessDay(DateFor(2021, 5, 3)))
+	assert.Equal(DateFor(2021, 5, 4), joint.NextBusinessDay(DateFor(2021, 4, 29)))
+	assert.Equal(DateFor(2021, 5, 6), joint.AddBusinessDays(DateFor(2021, 4, 29), 3))
+	assert.Equal(joint.Weekend(), JointBusinessCalendar(nil, gulf, nil, uk).Weekend())
+	assert.False(JointBusinessCalendar(nil, gulf, nil, uk).IsBusinessDay(DateFor(2021, 5, 3)))
+
+	// safe for concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(4, joint.BusinessDaysBetween(DateFor(2021, 4, 26), DateFor(2021, 5, 3)))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBusinessCalendarNeverOpen(t *testing.T) {
+	assert := assert.New(t)
+	cal := NewBusinessCalendar(AllWeekdays)
+	assert.True(cal.NextBusinessDay(DateFor(2021, 1, 1)).IsZero())
+	assert.True(cal.AddBusinessDays(DateFor(2021, 1, 1), 2).IsZero())
+
+	closed := NewBusinessCalendar(NoWeekdays, HolidayFunc(func(Date) bool { return true }))
+	assert.True(closed.Adjust(DateFor(2021, 1, 1), Following).IsZero())
+}
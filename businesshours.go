@@ -0,0 +1,227 @@
+package civil
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var errInvalidDayHours = errors.New("invalid business hours: start must be before end, and both within the day")
+
+var errNeverOpen = errors.New("business hours are not open for ten years")
+
+// DayHours is a period of business hours on one or more days of the week.
+// End must be after Start, and may be 24 hours for hours that last until
+// midnight.
+type DayHours struct {
+	Days Weekdays
+	TimeOfDayRange
+}
+
+// String returns the hours in the format "Mon-Fri 09:00-17:00".
+func (dh DayHours) String() string {
+	return dh.Days.String() + " " + dh.TimeOfDayRange.String()
+}
+
+// BusinessHours are the hours that a business is open, such as 09:00 to
+// 17:00 Monday to Friday, excluding holidays. It measures durations in
+// business hours, which is useful for deadlines such as "respond within
+// 8 business hours".
+//
+// All calculations use civil date-times. Use In to work with instants in
+// a time zone.
+//
+// A BusinessHours is immutable, and is safe for concurrent use by multiple
+// goroutines provided its holiday source is.
+type BusinessHours struct {
+	days     [7][]DayHours // indexed by weekday, sorted and merged
+	holidays HolidaySource
+}
+
+// NewBusinessHours returns the business hours that are open during the hours,
+// except on holidays. The holiday source may be nil. A lunch break can be
+// specified with two periods on the same days, such as 09:00 to 12:30 and
+// 13:30 to 17:00. Periods that overlap are combined.
+func NewBusinessHours(holidays HolidaySource, hours ...DayHours) (*BusinessHours, error) {
+	h := &BusinessHours{holidays: holidays}
+	for _, dh := range hours {
+		if dh.Start < 0 || dh.End > 24*time.Hour || dh.Start >= dh.End {
+			return nil, errInvalidDayHours
+		}
+		for _, day := range dh.Days.Days(time.Sunday) {
+			h.days[day] = append(h.days[day], DayHours{Days: WeekdaysOf(day), TimeOfDayRange: dh.TimeOfDayRange})
+		}
+	}
+	for day, ranges := range h.days {
+		h.days[day] = mergeRanges(ranges)
+	}
+	return h, nil
+}
+
+// mergeRanges sorts the hours and combines those that overlap or touch.
+func mergeRanges(ranges []DayHours) []DayHours {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	var merged []DayHours
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			if r.End > merged[n-1].End {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// IsOpen reports whether dt is within business hours.
+func (h *BusinessHours) IsOpen(dt DateTime) bool {
+	for _, r := range h.openOn(DateFor(dt.Date())) {
+		if r.Contains(dt) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBusinessDuration returns the date-time that is d business hours after
+// dt, or before dt if d is negative. Time outside business hours is not
+// counted, so with business hours of 09:00 to 17:00 Monday to Friday, 8 hours
+// after 15:00 on a Friday is 15:00 on the following Monday.
+//
+// A deadline that falls exactly at the end of business hours is returned
+// as the closing time, not the next opening time. If d is zero and dt is
+// outside business hours, the result is the next opening time. AddBusinessDuration
+// returns the zero DateTime if business hours are not open for ten years.
+func (h *BusinessHours) AddBusinessDuration(dt DateTime, d time.Duration) DateTime {
+	date := DateFor(dt.Date())
+	// closed counts the consecutive days without business hours
+	if d >= 0 {
+		for closed := 0; closed < maxBusinessDaySearch; {
+			ranges := h.openOn(date)
+			if len(ranges) == 0 {
+				closed++
+			} else {
+				closed = 0
+			}
+			for _, r := range ranges {
+				if r.End.Before(dt) || r.End.Equal(dt) && d > 0 {
+					continue
+				}
+				if r.Start.After(dt) {
+					dt = r.Start
+				}
+				remaining := r.End.Sub(dt)
+				if d <= remaining {
+					return dt.Add(d)
+				}
+				d -= remaining
+			}
+			date = date.AddDate(0, 0, 1)
+		}
+		return DateTime{}
+	}
+
+	for closed := 0; closed < maxBusinessDaySearch; {
+		ranges := h.openOn(date)
+		if len(ranges) == 0 {
+			closed++
+		} else {
+			closed = 0
+		}
+		for j := len(ranges) - 1; j >= 0; j-- {
+			r := ranges[j]
+			if !r.Start.Before(dt) {
+				continue
+			}
+			if r.End.Before(dt) {
+				dt = r.End
+			}
+			available := dt.Sub(r.Start)
+			if -d <= available {
+				return dt.Add(d)
+			}
+			d += available
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	return DateTime{}
+}
+
+// BusinessDurationBetween returns the business hours from a up to b.
+// If b is before a, the result is negative.
+func (h *BusinessHours) BusinessDurationBetween(a, b DateTime) time.Duration {
+	sign := time.Duration(1)
+	if b.Before(a) {
+		a, b, sign = b, a, -1
+	}
+	var total time.Duration
+	span := DateTimeRange{Start: a, End: b}
+	for date := DateFor(a.Date()); !date.After(DateFor(b.Date())); date = date.AddDate(0, 0, 1) {
+		for _, r := range h.openOn(date) {
+			if r = r.Intersect(span); !r.IsEmpty() {
+				total += r.Duration()
+			}
+		}
+	}
+	return sign * total
+}
+
+// openOn returns the business hours on date, which are empty on a holiday.
+func (h *BusinessHours) openOn(date Date) []DateTimeRange {
+	ranges := h.days[date.Weekday()]
+	if len(ranges) == 0 || h.holidays != nil && h.holidays.IsHoliday(date) {
+		return nil
+	}
+	year, month, day := date.Date()
+	midnight := DateTimeFor(year, month, day, 0, 0, 0)
+	result := make([]DateTimeRange, len(ranges))
+	for i, r := range ranges {
+		result[i] = DateTimeRange{Start: midnight.Add(r.Start), End: midnight.Add(r.End)}
+	}
+	return result
+}
+
+// ZonedBusinessHours are business hours in a time zone, which work with
+// instants instead of civil date-times.
+type ZonedBusinessHours struct {
+	hours          *BusinessHours
+	loc            *time.Location
+	disambiguation Disambiguation
+}
+
+// In returns the business hours in the time zone. Business hours are
+// calculated in civil time, and results that fall in a gap or an overlap
+// in the time zone are resolved using disambiguation.
+func (h *BusinessHours) In(loc *time.Location, disambiguation Disambiguation) *ZonedBusinessHours {
+	return &ZonedBusinessHours{hours: h, loc: loc, disambiguation: disambiguation}
+}
+
+// Location returns the time zone of the business hours.
+func (z *ZonedBusinessHours) Location() *time.Location {
+	return z.loc
+}
+
+// IsOpen reports whether t is within business hours.
+func (z *ZonedBusinessHours) IsOpen(t time.Time) bool {
+	return z.hours.IsOpen(DateTimeOf(t.In(z.loc)))
+}
+
+// AddBusinessDuration returns the instant that is d business hours after t,
+// or before t if d is negative. It returns an error if business hours are
+// not open for ten years.
+func (z *ZonedBusinessHours) AddBusinessDuration(t time.Time, d time.Duration) (time.Time, error) {
+	dt := z.hours.AddBusinessDuration(DateTimeOf(t.In(z.loc)), d)
+	if dt.IsZero() {
+		return time.Time{}, errNeverOpen
+	}
+	return dt.In(z.loc, z.disambiguation)
+}
+
+// BusinessDurationBetween returns the business hours from a up to b.
+// If b is before a, the result is negative.
+func (z *ZonedBusinessHours) BusinessDurationBetween(a, b time.Time) time.Duration {
+	return z.hours.BusinessDurationBetween(DateTimeOf(a.In(z.loc)), DateTimeOf(b.In(z.loc)))
+}
@@ -0,0 +1,123 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBusinessHours(t *testing.T) *BusinessHours {
+	// 09:00-12:30 and 13:30-17:00 Monday to Friday, 10:00-14:00 Saturday,
+	// closed on Monday 1 March 2021
+	h, err := NewBusinessHours(HolidayDates(DateFor(2021, 3, 1)),
+		DayHours{Days: MondayToFriday, TimeOfDayRange: TimeOfDayRange{Start: 9 * time.Hour, End: 12*time.Hour + 30*time.Minute}},
+		DayHours{Days: MondayToFriday, TimeOfDayRange: TimeOfDayRange{Start: 13*time.Hour + 30*time.Minute, End: 17 * time.Hour}},
+		DayHours{Days: WeekdaysOf(time.Saturday), TimeOfDayRange: TimeOfDayRange{Start: 10 * time.Hour, End: 14 * time.Hour}},
+		// overlaps and is merged with 13:30-17:00
+		DayHours{Days: WeekdaysOf(time.Friday), TimeOfDayRange: TimeOfDayRange{Start: 16 * time.Hour, End: 17 * time.Hour}},
+	)
+	require.NoError(t, err)
+	return h
+}
+
+func TestBusinessHoursIsOpen(t *testing.T) {
+	h := testBusinessHours(t)
+	testCases := []struct {
+		DateTime string
+		Open     bool
+	}{
+		{"2021-03-01T10:00", false}, // holiday
+		{"2021-03-02T08:59:59", false},
+		{"2021-03-02T09:00", true},
+		{"2021-03-02T12:30", false},
+		{"2021-03-02T13:30", true},
+		{"2021-03-02T16:59:59", true},
+		{"2021-03-02T17:00", false},
+		{"2021-03-06T11:00", true},
+		{"2021-03-07T11:00", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Open, h.IsOpen(mustParseDateTime(tc.DateTime)), tc.DateTime)
+	}
+}
+
+func TestBusinessHoursAdd(t *testing.T) {
+	h := testBusinessHours(t)
+	testCases := []struct {
+		Start    string
+		Duration time.Duration
+		Expected string
+	}{
+		{"2021-03-02T09:00", 8 * time.Hour, "2021-03-03T10:00:00"},
+		{"2021-03-02T12:00", time.Hour, "2021-03-02T14:00:00"},
+		{"2021-03-02T12:00", 30 * time.Minute, "2021-03-02T12:30:00"},
+		{"2021-03-02T18:00", time.Hour, "2021-03-03T10:00:00"},
+		{"2021-03-02T18:00", 0, "2021-03-03T09:00:00"},
+		{"2021-03-02T10:15", 0, "2021-03-02T10:15:00"},
+		{"2021-03-05T16:00", 2 * time.Hour, "2021-03-06T11:00:00"},
+		{"2021-03-05T16:00", 6 * time.Hour, "2021-03-08T10:00:00"},
+		// skips the holiday
+		{"2021-02-27T13:00", 2 * time.Hour, "2021-03-02T10:00:00"},
+		// backwards
+		{"2021-03-03T10:00", -8 * time.Hour, "2021-03-02T09:00:00"},
+		{"2021-03-02T14:00", -time.Hour, "2021-03-02T12:00:00"},
+		{"2021-03-02T10:00", -2 * time.Hour, "2021-02-27T13:00:00"},
+		{"2021-03-07T12:00", -time.Hour, "2021-03-06T13:00:00"},
+	}
+	for _, tc := range testCases {
+		start := mustParseDateTime(tc.Start)
+		actual := h.AddBusinessDuration(start, tc.Duration)
+		assert.Equal(t, tc.Expected, actual.String(), "%s %v", tc.Start, tc.Duration)
+		if tc.Duration != 0 && h.IsOpen(start) {
+			assert.Equal(t, tc.Duration, h.BusinessDurationBetween(start, actual), "%s %v", tc.Start, tc.Duration)
+		}
+	}
+
+	never, err := NewBusinessHours(HolidayFunc(func(Date) bool { return true }), DayHours{Days: AllWeekdays, TimeOfDayRange: TimeOfDayRange{End: 24 * time.Hour}})
+	require.NoError(t, err)
+	assert.True(t, never.AddBusinessDuration(mustParseDateTime("2021-03-01T00:00"), time.Hour).IsZero())
+	assert.True(t, never.AddBusinessDuration(mustParseDateTime("2021-03-01T00:00"), -time.Hour).IsZero())
+	_, err = never.In(time.UTC, DisambiguateCompatible).AddBusinessDuration(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Hour)
+	assert.Error(t, err)
+
+	// durations of more than ten years of business time
+	always, err := NewBusinessHours(nil, DayHours{Days: AllWeekdays, TimeOfDayRange: TimeOfDayRange{End: 24 * time.Hour}})
+	require.NoError(t, err)
+	long := 20 * 366 * 24 * time.Hour
+	start := mustParseDateTime("2021-03-01T00:00")
+	assert.Equal(t, start.Add(long), always.AddBusinessDuration(start, long))
+	assert.Equal(t, start.Add(-long), always.AddBusinessDuration(start, -long))
+	deadline, err := always.In(time.UTC, DisambiguateCompatible).AddBusinessDuration(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), long)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC).Add(long), deadline)
+}
+
+func TestBusinessHoursBetween(t *testing.T) {
+	h := testBusinessHours(t)
+	a, b := mustParseDateTime("2021-02-26T16:00"), mustParseDateTime("2021-03-02T10:00")
+	assert.Equal(t, 6*time.Hour, h.BusinessDurationBetween(a, b))
+	assert.Equal(t, -6*time.Hour, h.BusinessDurationBetween(b, a))
+	assert.Equal(t, time.Duration(0), h.BusinessDurationBetween(a, a))
+
+	assert.Equal(t, "Mon-Fri 09:00-17:00", DayHours{Days: MondayToFriday, TimeOfDayRange: TimeOfDayRange{Start: 9 * time.Hour, End: 17 * time.Hour}}.String())
+	_, err := NewBusinessHours(nil, DayHours{Days: MondayToFriday, TimeOfDayRange: TimeOfDayRange{Start: 17 * time.Hour, End: 9 * time.Hour}})
+	assert.Error(t, err)
+	_, err = NewBusinessHours(nil, DayHours{Days: MondayToFriday, TimeOfDayRange: TimeOfDayRange{Start: 9 * time.Hour, End: 25 * time.Hour}})
+	assert.Error(t, err)
+}
+
+func TestZonedBusinessHours(t *testing.T) {
+	sydney := mustLoadLocation("Australia/Sydney")
+	z := testBusinessHours(t).In(sydney, DisambiguateCompatible)
+	assert.Equal(t, sydney, z.Location())
+
+	// 23:00 UTC on 1 March is 10:00 on 2 March in Sydney
+	start := time.Date(2021, 3, 1, 23, 0, 0, 0, time.UTC)
+	assert.True(t, z.IsOpen(start))
+	deadline, err := z.AddBusinessDuration(start, 8*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "2021-03-03T11:00:00+11:00", deadline.Format(time.RFC3339))
+	assert.Equal(t, 8*time.Hour, z.BusinessDurationBetween(start, deadline))
+}
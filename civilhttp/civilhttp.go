@@ -0,0 +1,85 @@
+// Package civilhttp provides HTTP middleware that records the time zone
+// of the user making a request in the request's context, so that
+// civil.TodayCtx and civil.NowCtx return the user's date and time
+// rather than the server's.
+package civilhttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Default names of the header and cookie that contain the user's
+// IANA time zone name, such as "Australia/Sydney".
+const (
+	DefaultHeader = "Time-Zone"
+	DefaultCookie = "tz"
+)
+
+// ZoneReader reads the user's time zone from a request.
+type ZoneReader struct {
+	// Header is the name of the request header containing the time zone.
+	// If empty, no header is read.
+	Header string
+
+	// Cookie is the name of the cookie containing the time zone.
+	// If empty, no cookie is read.
+	Cookie string
+
+	// Fallback is the location used when the request does not specify
+	// a valid time zone. If nil, no location is added to the context, and
+	// civil.TodayCtx and civil.NowCtx use the fallback clock.
+	Fallback *time.Location
+}
+
+// Location returns the time zone for the request. The header is checked
+// before the cookie. Unknown time zone names are ignored.
+func (z ZoneReader) Location(r *http.Request) (*time.Location, bool) {
+	if z.Header != "" {
+		if loc, ok := lookup(r.Header.Get(z.Header)); ok {
+			return loc, true
+		}
+	}
+	if z.Cookie != "" {
+		if cookie, err := r.Cookie(z.Cookie); err == nil {
+			if loc, ok := lookup(cookie.Value); ok {
+				return loc, true
+			}
+		}
+	}
+	if z.Fallback != nil {
+		return z.Fallback, true
+	}
+	return nil, false
+}
+
+// Middleware returns a handler that adds the request's time zone to the
+// request context using civil.WithLocation, and then calls next.
+func (z ZoneReader) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if loc, ok := z.Location(r); ok {
+			r = r.WithContext(civil.WithLocation(r.Context(), loc))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Middleware returns a handler that reads the time zone from the
+// DefaultHeader header or the DefaultCookie cookie, and then calls next.
+func Middleware(next http.Handler) http.Handler {
+	return ZoneReader{Header: DefaultHeader, Cookie: DefaultCookie}.Middleware(next)
+}
+
+func lookup(name string) (*time.Location, bool) {
+	if name == "" || name == "Local" {
+		// "Local" is the server's zone, not the user's
+		return nil, false
+	}
+	loc, err := civil.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,77 @@
+package civilhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	clock := civil.NewFakeClock(time.Date(2021, 3, 1, 14, 0, 0, 0, time.UTC))
+	civil.SetFallbackClock(clock)
+	defer civil.SetFallbackClock(nil)
+
+	var today, now string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		today = civil.TodayCtx(r.Context()).String()
+		now = civil.NowCtx(r.Context()).String()
+	}))
+
+	testCases := []struct {
+		Header string
+		Cookie string
+		Today  string
+		Now    string
+	}{
+		{Today: "2021-03-01", Now: "2021-03-01T14:00:00"},
+		{Header: "Australia/Sydney", Today: "2021-03-02", Now: "2021-03-02T01:00:00"},
+		{Cookie: "America/Los_Angeles", Today: "2021-03-01", Now: "2021-03-01T06:00:00"},
+		{Header: "Australia/Sydney", Cookie: "America/Los_Angeles", Today: "2021-03-02", Now: "2021-03-02T01:00:00"},
+		{Header: "Nowhere/Special", Cookie: "Pacific/Auckland", Today: "2021-03-02", Now: "2021-03-02T03:00:00"},
+		{Header: "Nowhere/Special", Today: "2021-03-01", Now: "2021-03-01T14:00:00"},
+		{Header: "Local", Today: "2021-03-01", Now: "2021-03-01T14:00:00"},
+	}
+
+	for _, tc := range testCases {
+		r := httptest.NewRequest("GET", "/", nil)
+		if tc.Header != "" {
+			r.Header.Set(DefaultHeader, tc.Header)
+		}
+		if tc.Cookie != "" {
+			r.AddCookie(&http.Cookie{Name: DefaultCookie, Value: tc.Cookie})
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		assert.Equal(tc.Today, today, "%s %s", tc.Header, tc.Cookie)
+		assert.Equal(tc.Now, now, "%s %s", tc.Header, tc.Cookie)
+	}
+}
+
+func TestZoneReaderFallback(t *testing.T) {
+	assert := assert.New(t)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(err)
+	z := ZoneReader{Header: "X-TZ", Fallback: tokyo}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	loc, ok := z.Location(r)
+	assert.True(ok)
+	assert.Equal(tokyo, loc)
+
+	r.Header.Set("X-TZ", "Europe/London")
+	loc, ok = z.Location(r)
+	assert.True(ok)
+	assert.Equal("Europe/London", loc.String())
+
+	r.Header.Set(DefaultHeader, "Europe/Paris")
+	r.Header.Del("X-TZ")
+	loc, _ = z.Location(r)
+	assert.Equal(tokyo, loc)
+
+	_, ok = ZoneReader{}.Location(r)
+	assert.False(ok)
+}
@@ -0,0 +1,133 @@
+package civil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time. Business logic that obtains the
+// current date or date-time from a Clock, rather than from Today or Now,
+// can be tested with a FakeClock, and can be given a clock in the time
+// zone of the user it is working for.
+type Clock interface {
+	// Now returns the current time. The location of the result
+	// determines the civil date and time.
+	Now() time.Time
+}
+
+type systemClock struct {
+	loc *time.Location
+}
+
+// SystemClock returns a clock that reads the system time in loc.
+// If loc is nil, the clock uses time.Local.
+func SystemClock(loc *time.Location) Clock {
+	if loc == nil {
+		loc = time.Local
+	}
+	return systemClock{loc: loc}
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+// FakeClock is a Clock whose time only changes when it is set or advanced.
+// It is intended for testing. A FakeClock is safe for concurrent use by
+// multiple goroutines.
+type FakeClock struct {
+	mutex    sync.Mutex
+	t        time.Time
+	watchers map[chan<- struct{}]bool
+}
+
+// manualClock is implemented by clocks, such as FakeClock, whose time only
+// changes when it is changed explicitly. Instead of waiting for real time
+// to pass, a Scheduler watches the clock and checks its timers whenever
+// the time changes.
+type manualClock interface {
+	// watch causes the clock to send on ch, without blocking, whenever
+	// its time changes.
+	watch(ch chan<- struct{})
+
+	// unwatch stops the clock sending on ch.
+	unwatch(ch chan<- struct{})
+}
+
+// NewFakeClock returns a fake clock whose current time is t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{t: t}
+}
+
+// Now returns the current time of the fake clock.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.t
+}
+
+// Set changes the current time of the fake clock to t. The time may
+// move backwards, and may be in a different location, which simulates
+// a change in the system time zone. Any Scheduler using the clock
+// re-evaluates its timers.
+func (c *FakeClock) Set(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.t = t
+	c.notify()
+}
+
+// Advance moves the current time of the fake clock forward by d.
+// Any Scheduler timers that become due are fired.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.t = c.t.Add(d)
+	c.notify()
+}
+
+// notify tells the watchers that the time has changed. A watcher that
+// already has a notification pending does not need another one.
+func (c *FakeClock) notify() {
+	for ch := range c.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *FakeClock) watch(ch chan<- struct{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.watchers == nil {
+		c.watchers = make(map[chan<- struct{}]bool)
+	}
+	c.watchers[ch] = true
+}
+
+func (c *FakeClock) unwatch(ch chan<- struct{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.watchers, ch)
+}
+
+// TodayFrom returns the current civil date according to clock.
+func TodayFrom(clock Clock) Date {
+	return DateOf(clock.Now())
+}
+
+// NowFrom returns the current civil date-time according to clock.
+func NowFrom(clock Clock) DateTime {
+	return DateTimeOf(clock.Now())
+}
+
+// TodayIn returns the current civil date in loc.
+func TodayIn(loc *time.Location) Date {
+	return TodayFrom(SystemClock(loc))
+}
+
+// NowIn returns the current civil date-time in loc.
+func NowIn(loc *time.Location) DateTime {
+	return NowFrom(SystemClock(loc))
+}
@@ -0,0 +1,65 @@
+package civil
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	clock := NewFakeClock(time.Date(2021, 3, 1, 23, 30, 0, 0, sydney))
+
+	assert.Equal("2021-03-01", TodayFrom(clock).String())
+	assert.Equal("2021-03-01T23:30:00", NowFrom(clock).String())
+
+	clock.Advance(45 * time.Minute)
+	assert.Equal("2021-03-02", TodayFrom(clock).String())
+	assert.Equal("2021-03-02T00:15:00", NowFrom(clock).String())
+
+	// same instant, different zone
+	clock.Set(clock.Now().In(time.UTC))
+	assert.Equal("2021-03-01", TodayFrom(clock).String())
+	assert.Equal("2021-03-01T13:15:00", NowFrom(clock).String())
+
+	// safe for concurrent use
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Minute)
+			_ = NowFrom(clock)
+		}()
+	}
+	wg.Wait()
+	assert.Equal("2021-03-01T13:25:00", NowFrom(clock).String())
+}
+
+func TestSystemClock(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+
+	before := time.Now()
+	now := SystemClock(sydney).Now()
+	after := time.Now()
+	assert.Equal(sydney, now.Location())
+	assert.False(now.Before(before))
+	assert.False(now.After(after))
+	assert.Equal(time.Local, SystemClock(nil).Now().Location())
+
+	// the date in the zone is either the date before or after the call
+	d1 := DateOf(time.Now().In(sydney))
+	today := TodayIn(sydney)
+	d2 := DateOf(time.Now().In(sydney))
+	assert.True(today.Equal(d1) || today.Equal(d2))
+
+	dt1 := DateTimeOf(time.Now().In(sydney))
+	now2 := NowIn(sydney)
+	dt2 := DateTimeOf(time.Now().In(sydney))
+	assert.False(now2.Before(dt1))
+	assert.False(now2.After(dt2))
+}
@@ -0,0 +1,92 @@
+package civil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+const (
+	locationKey contextKey = iota
+	clockKey
+)
+
+// fallback is the clock used by ClockFromContext when
+// the context has neither a clock nor a location.
+var fallback = struct {
+	sync.RWMutex
+	clock Clock
+}{
+	clock: SystemClock(time.Local),
+}
+
+// SetFallbackClock sets the clock used by TodayCtx, NowCtx and ClockFromContext
+// when the context has neither a clock nor a location. Initially the fallback is
+// the system clock in time.Local. If clock is nil, the initial fallback is restored.
+func SetFallbackClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock(time.Local)
+	}
+	fallback.Lock()
+	fallback.clock = clock
+	fallback.Unlock()
+}
+
+func fallbackClock() Clock {
+	fallback.RLock()
+	defer fallback.RUnlock()
+	return fallback.clock
+}
+
+// WithLocation returns a copy of ctx that carries loc, which is usually
+// the time zone of the user on whose behalf a request is being handled.
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey, loc)
+}
+
+// WithClock returns a copy of ctx that carries clock.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey, clock)
+}
+
+// LocationFromContext returns the location carried by ctx, if any.
+func LocationFromContext(ctx context.Context) (*time.Location, bool) {
+	loc, ok := ctx.Value(locationKey).(*time.Location)
+	return loc, ok && loc != nil
+}
+
+// ClockFromContext returns the clock to use for ctx. If ctx carries a clock,
+// that clock is used, otherwise the fallback clock is used. If ctx carries
+// a location, the clock's time is converted to that location.
+func ClockFromContext(ctx context.Context) Clock {
+	clock, ok := ctx.Value(clockKey).(Clock)
+	if !ok || clock == nil {
+		clock = fallbackClock()
+	}
+	if loc, ok := LocationFromContext(ctx); ok {
+		return locationClock{clock: clock, loc: loc}
+	}
+	return clock
+}
+
+// TodayCtx returns the current civil date for ctx. See ClockFromContext.
+func TodayCtx(ctx context.Context) Date {
+	return TodayFrom(ClockFromContext(ctx))
+}
+
+// NowCtx returns the current civil date-time for ctx. See ClockFromContext.
+func NowCtx(ctx context.Context) DateTime {
+	return NowFrom(ClockFromContext(ctx))
+}
+
+// locationClock converts the time of another clock to a location.
+type locationClock struct {
+	clock Clock
+	loc   *time.Location
+}
+
+func (c locationClock) Now() time.Time {
+	return c.clock.Now().In(c.loc)
+}
@@ -0,0 +1,43 @@
+package civil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	clock := NewFakeClock(time.Date(2021, 3, 1, 14, 0, 0, 0, time.UTC))
+	ctx := context.Background()
+
+	_, ok := LocationFromContext(ctx)
+	assert.False(ok)
+
+	SetFallbackClock(clock)
+	defer SetFallbackClock(nil)
+	assert.Equal("2021-03-01", TodayCtx(ctx).String())
+	assert.Equal("2021-03-01T14:00:00", NowCtx(ctx).String())
+
+	userCtx := WithLocation(ctx, sydney)
+	loc, ok := LocationFromContext(userCtx)
+	assert.True(ok)
+	assert.Equal(sydney, loc)
+	assert.Equal("2021-03-02", TodayCtx(userCtx).String())
+	assert.Equal("2021-03-02T01:00:00", NowCtx(userCtx).String())
+
+	// a clock in the context takes precedence over the fallback
+	other := NewFakeClock(time.Date(2021, 6, 30, 13, 59, 0, 0, time.UTC))
+	clockCtx := WithClock(userCtx, other)
+	assert.Equal("2021-06-30T23:59:00", NowCtx(clockCtx).String())
+	assert.Equal("2021-06-30T13:59:00", NowCtx(WithClock(ctx, other)).String())
+
+	// nil values are ignored
+	assert.Equal("2021-03-01T14:00:00", NowCtx(WithLocation(WithClock(ctx, nil), nil)).String())
+
+	SetFallbackClock(nil)
+	assert.Equal(time.Local, ClockFromContext(ctx).Now().Location())
+}
@@ -0,0 +1,454 @@
+// Package cron evaluates cron expressions as civil date-times.
+//
+// A cron expression such as "30 2 * * *" describes wall-clock times, so the
+// schedule is computed in civil time. Use Schedule.In to bind a schedule
+// to a time zone, which says how times that are skipped or repeated by
+// daylight saving transitions are handled.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// maxDays is the number of days searched for the next or previous time
+// before concluding that a schedule never matches, such as "0 0 30 2 *".
+// It is the 400 year cycle of the Gregorian calendar, after which dates
+// fall on the same days of the week. A sparse schedule such as
+// "0 0 * 2 MON#5" can take 40 years to recur, because 2100 is not a
+// leap year.
+const maxDays = 146097
+
+const secondsPerDay = 24 * 60 * 60
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	expr string
+
+	second, minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar are set if the day-of-month or day-of-week field
+	// starts with "*" or "?", in which case a day must match both fields.
+	// Otherwise a day matches if either field matches, which is the
+	// traditional cron behaviour.
+	domStar, dowStar bool
+
+	lastDays []int    // L and L-n: days before the last day of the month
+	nearest  []int    // nW: nearest weekday to day n, 0 for LW
+	lastDow  uint64   // nL: last weekday n of the month
+	nthDow   []nthDow // n#k: k'th weekday n of the month
+}
+
+type nthDow struct {
+	weekday time.Weekday
+	n       int
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+var dowNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// Parse parses a cron expression with five fields (minute, hour, day of month,
+// month and day of week) or six fields (with seconds first).
+//
+// Each field is "*", a value, a range "a-b", or a list of these separated by
+// commas, and ranges and "*" may have a step such as "*/15". Months and days of
+// the week may be given as three-letter names, and Sunday is 0 or 7. "?" is the
+// same as "*" in the day fields. The day-of-month field also accepts "L" for the
+// last day, "L-n" for n days before the last day, "nW" for the weekday nearest
+// day n, and "LW" for the last weekday. The day-of-week field also accepts "nL"
+// for the last day n of the month and "n#k" for the k'th day n of the month.
+// The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are also accepted.
+func Parse(expr string) (*Schedule, error) {
+	text := strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(text)]; ok {
+		text = macro
+	}
+	fields := strings.Fields(text)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron: expected 5 or 6 fields in %q", expr)
+	}
+	s := &Schedule{expr: expr}
+	var err error
+	if s.second, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron: second: %v", err)
+	}
+	if s.minute, err = parseField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron: minute: %v", err)
+	}
+	if s.hour, err = parseField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron: hour: %v", err)
+	}
+	if err = s.parseDom(fields[3]); err != nil {
+		return nil, fmt.Errorf("cron: day of month: %v", err)
+	}
+	if s.month, err = parseField(fields[4], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("cron: month: %v", err)
+	}
+	if err = s.parseDow(fields[5]); err != nil {
+		return nil, fmt.Errorf("cron: day of week: %v", err)
+	}
+	return s, nil
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed.
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err.Error())
+	}
+	return s
+}
+
+// String returns the expression that was parsed.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+func (s *Schedule) parseDom(field string) error {
+	s.domStar = field[0] == '*' || field[0] == '?'
+	var plain []string
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case upper == "L":
+			s.lastDays = append(s.lastDays, 0)
+		case upper == "LW":
+			s.nearest = append(s.nearest, 0)
+		case strings.HasPrefix(upper, "L-"):
+			n, err := parseValue(upper[2:], 0, 30, nil)
+			if err != nil {
+				return err
+			}
+			s.lastDays = append(s.lastDays, n)
+		case strings.HasSuffix(upper, "W"):
+			n, err := parseValue(upper[:len(upper)-1], 1, 31, nil)
+			if err != nil {
+				return err
+			}
+			s.nearest = append(s.nearest, n)
+		default:
+			plain = append(plain, item)
+		}
+	}
+	if len(plain) > 0 {
+		var err error
+		s.dom, err = parseField(strings.Join(plain, ","), 1, 31, nil)
+		return err
+	}
+	return nil
+}
+
+func (s *Schedule) parseDow(field string) error {
+	s.dowStar = field[0] == '*' || field[0] == '?'
+	var plain []string
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		if i := strings.IndexByte(upper, '#'); i >= 0 {
+			wd, err := parseValue(upper[:i], 0, 7, dowNames)
+			if err != nil {
+				return err
+			}
+			n, err := parseValue(upper[i+1:], 1, 5, nil)
+			if err != nil {
+				return err
+			}
+			s.nthDow = append(s.nthDow, nthDow{weekday: time.Weekday(wd % 7), n: n})
+		} else if len(upper) > 1 && strings.HasSuffix(upper, "L") {
+			wd, err := parseValue(upper[:len(upper)-1], 0, 7, dowNames)
+			if err != nil {
+				return err
+			}
+			s.lastDow |= 1 << uint(wd%7)
+		} else {
+			plain = append(plain, item)
+		}
+	}
+	if len(plain) > 0 {
+		bits, err := parseField(strings.Join(plain, ","), 0, 7, dowNames)
+		if err != nil {
+			return err
+		}
+		// 7 is another name for Sunday
+		if bits&(1<<7) != 0 {
+			bits = bits&^(1<<7) | 1
+		}
+		s.dow = bits
+	}
+	return nil
+}
+
+// parseField parses a list of values, ranges and steps, returning a bit mask
+// with bit n set for each value n.
+func parseField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			rangePart = item[:i]
+			var err error
+			if step, err = parseValue(item[i+1:], 1, max, nil); err != nil {
+				return 0, err
+			}
+		}
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.IndexByte(rangePart, '-') > 0:
+			i := strings.IndexByte(rangePart, '-')
+			var err error
+			if lo, err = parseValue(rangePart[:i], min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(rangePart[i+1:], min, max, names); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if lo, err = parseValue(rangePart, min, max, names); err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				hi = lo
+			}
+		}
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max int, names []string) (int, error) {
+	for n, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return n, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+	}
+	return n, nil
+}
+
+// Next returns the first time in the schedule after dt, or false if
+// there is none.
+func (s *Schedule) Next(dt civil.DateTime) (civil.DateTime, bool) {
+	d := dateOf(dt)
+	tod := timeOfDay(dt) + 1
+	for i := 0; i < maxDays; i++ {
+		if tod < secondsPerDay && s.matchDay(d) {
+			if t, ok := s.nextTimeOfDay(tod); ok {
+				return midnight(d).Add(time.Duration(t) * time.Second), true
+			}
+		}
+		d, tod = d.AddDate(0, 0, 1), 0
+	}
+	return civil.DateTime{}, false
+}
+
+// Prev returns the last time in the schedule before dt, or false if
+// there is none.
+func (s *Schedule) Prev(dt civil.DateTime) (civil.DateTime, bool) {
+	d := dateOf(dt)
+	tod := timeOfDay(dt) - 1
+	for i := 0; i < maxDays; i++ {
+		if tod >= 0 && s.matchDay(d) {
+			if t, ok := s.prevTimeOfDay(tod); ok {
+				return midnight(d).Add(time.Duration(t) * time.Second), true
+			}
+		}
+		d, tod = d.AddDate(0, 0, -1), secondsPerDay-1
+	}
+	return civil.DateTime{}, false
+}
+
+// Between returns the times in the schedule from a up to, but not
+// including, b.
+func (s *Schedule) Between(a, b civil.DateTime) []civil.DateTime {
+	var times []civil.DateTime
+	for dt, ok := s.Next(a.Add(-time.Second)); ok && dt.Before(b); dt, ok = s.Next(dt) {
+		times = append(times, dt)
+	}
+	return times
+}
+
+// nextTimeOfDay returns the first time of day, in seconds since midnight,
+// that is at or after tod.
+func (s *Schedule) nextTimeOfDay(tod int) (int, bool) {
+	h0, m0, s0 := tod/3600, tod/60%60, tod%60
+	for h := h0; h < 24; h++ {
+		if !has(s.hour, h) {
+			continue
+		}
+		m := 0
+		if h == h0 {
+			m = m0
+		}
+		for ; m < 60; m++ {
+			if !has(s.minute, m) {
+				continue
+			}
+			sec := 0
+			if h == h0 && m == m0 {
+				sec = s0
+			}
+			for ; sec < 60; sec++ {
+				if has(s.second, sec) {
+					return h*3600 + m*60 + sec, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// prevTimeOfDay returns the last time of day, in seconds since midnight,
+// that is at or before tod.
+func (s *Schedule) prevTimeOfDay(tod int) (int, bool) {
+	h0, m0, s0 := tod/3600, tod/60%60, tod%60
+	for h := h0; h >= 0; h-- {
+		if !has(s.hour, h) {
+			continue
+		}
+		m := 59
+		if h == h0 {
+			m = m0
+		}
+		for ; m >= 0; m-- {
+			if !has(s.minute, m) {
+				continue
+			}
+			sec := 59
+			if h == h0 && m == m0 {
+				sec = s0
+			}
+			for ; sec >= 0; sec-- {
+				if has(s.second, sec) {
+					return h*3600 + m*60 + sec, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchDay reports whether the schedule includes date d.
+func (s *Schedule) matchDay(d civil.Date) bool {
+	year, month, day := d.Date()
+	if !has(s.month, int(month)) {
+		return false
+	}
+	if s.domStar || s.dowStar {
+		return s.matchDom(year, month, day) && s.matchDow(d, year, month, day)
+	}
+	return s.matchDom(year, month, day) || s.matchDow(d, year, month, day)
+}
+
+func (s *Schedule) matchDom(year int, month time.Month, day int) bool {
+	if has(s.dom, day) {
+		return true
+	}
+	last := daysIn(year, month)
+	for _, n := range s.lastDays {
+		if day == last-n {
+			return true
+		}
+	}
+	for _, n := range s.nearest {
+		if n == 0 {
+			n = last
+		}
+		if day == nearestWeekday(year, month, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schedule) matchDow(d civil.Date, year int, month time.Month, day int) bool {
+	wd := d.Weekday()
+	if has(s.dow, int(wd)) {
+		return true
+	}
+	if has(s.lastDow, int(wd)) && day+7 > daysIn(year, month) {
+		return true
+	}
+	for _, nth := range s.nthDow {
+		if nth.weekday == wd && (day-1)/7+1 == nth.n {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestWeekday returns the day of the month of the weekday nearest to day n,
+// without leaving the month.
+func nearestWeekday(year int, month time.Month, n int) int {
+	last := daysIn(year, month)
+	if n > last {
+		n = last
+	}
+	switch civil.DateFor(year, month, n).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return n + 2
+		}
+		return n - 1
+	case time.Sunday:
+		if n == last {
+			return n - 2
+		}
+		return n + 1
+	}
+	return n
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
+
+func daysIn(year int, month time.Month) int {
+	return civil.DateFor(year, month+1, 0).Day()
+}
+
+func timeOfDay(dt civil.DateTime) int {
+	h, m, s := dt.Clock()
+	return h*3600 + m*60 + s
+}
+
+func midnight(d civil.Date) civil.DateTime {
+	year, month, day := d.Date()
+	return civil.DateTimeFor(year, month, day, 0, 0, 0)
+}
+
+func dateOf(dt civil.DateTime) civil.Date {
+	year, month, day := dt.Date()
+	return civil.DateFor(year, month, day)
+}
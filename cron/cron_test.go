@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseDateTime(s string) civil.DateTime {
+	dt, err := civil.ParseDateTime(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return dt
+}
+
+func TestNext(t *testing.T) {
+	testCases := []struct {
+		Expr     string
+		After    string
+		Expected []string
+	}{
+		{"30 2 * * *", "2021-03-01T02:30", []string{"2021-03-02T02:30:00", "2021-03-03T02:30:00"}},
+		{"*/15 * * * *", "2021-03-01T10:07", []string{"2021-03-01T10:15:00", "2021-03-01T10:30:00"}},
+		{"*/20 * * * * *", "2021-03-01T10:07:50", []string{"2021-03-01T10:08:00", "2021-03-01T10:08:20"}},
+		{"0 0 9-17 * * MON-FRI", "2021-03-05T17:00", []string{"2021-03-08T09:00:00", "2021-03-08T10:00:00"}},
+		{"0 9 1 jan,Jul *", "2021-03-01T00:00", []string{"2021-07-01T09:00:00", "2022-01-01T09:00:00"}},
+		{"0 0 */10 * *", "2021-03-01T00:00", []string{"2021-03-11T00:00:00", "2021-03-21T00:00:00", "2021-03-31T00:00:00", "2021-04-01T00:00:00"}},
+		{"0 12 L * *", "2021-02-01T00:00", []string{"2021-02-28T12:00:00", "2021-03-31T12:00:00"}},
+		{"0 12 L-2 * *", "2021-02-01T00:00", []string{"2021-02-26T12:00:00", "2021-03-29T12:00:00"}},
+		{"0 9 15W * *", "2021-05-01T00:00", []string{"2021-05-14T09:00:00", "2021-06-15T09:00:00", "2021-07-15T09:00:00", "2021-08-16T09:00:00"}},
+		{"0 9 1W * *", "2021-04-30T00:00", []string{"2021-05-03T09:00:00", "2021-06-01T09:00:00"}},
+		{"0 9 LW * *", "2021-07-01T00:00", []string{"2021-07-30T09:00:00", "2021-08-31T09:00:00"}},
+		{"0 9 ? * 5L", "2021-03-01T00:00", []string{"2021-03-26T09:00:00", "2021-04-30T09:00:00"}},
+		{"0 9 ? * FRI#3", "2021-03-01T00:00", []string{"2021-03-19T09:00:00", "2021-04-16T09:00:00"}},
+		{"0 0 29 2 *", "2021-03-01T00:00", []string{"2024-02-29T00:00:00", "2028-02-29T00:00:00"}},
+		// day of month or day of week
+		{"0 0 13 * 5", "2021-03-01T00:00", []string{"2021-03-05T00:00:00", "2021-03-12T00:00:00", "2021-03-13T00:00:00"}},
+		{"0 0 * * 5", "2021-03-01T00:00", []string{"2021-03-05T00:00:00", "2021-03-12T00:00:00", "2021-03-19T00:00:00"}},
+		{"0 0 * * 7", "2021-03-01T00:00", []string{"2021-03-07T00:00:00"}},
+		{"@weekly", "2021-03-01T00:00", []string{"2021-03-07T00:00:00", "2021-03-14T00:00:00"}},
+		{"@hourly", "2021-03-01T23:00", []string{"2021-03-02T00:00:00"}},
+		{"@yearly", "2021-03-01T00:00", []string{"2022-01-01T00:00:00"}},
+	}
+	for _, tc := range testCases {
+		s, err := Parse(tc.Expr)
+		if !assert.NoError(t, err, tc.Expr) {
+			continue
+		}
+		dt := mustParseDateTime(tc.After)
+		var actual []string
+		for range tc.Expected {
+			var ok bool
+			dt, ok = s.Next(dt)
+			assert.True(t, ok, tc.Expr)
+			actual = append(actual, dt.String())
+		}
+		assert.Equal(t, tc.Expected, actual, tc.Expr)
+
+		// Prev is the reverse of Next
+		for i := len(actual) - 1; i > 0; i-- {
+			prev, ok := s.Prev(mustParseDateTime(actual[i]))
+			assert.True(t, ok, tc.Expr)
+			assert.Equal(t, actual[i-1], prev.String(), tc.Expr)
+		}
+	}
+}
+
+func TestNever(t *testing.T) {
+	s := MustParse("0 0 30 2 *")
+	_, ok := s.Next(mustParseDateTime("2021-01-01T00:00"))
+	assert.False(t, ok)
+	_, ok = s.Prev(mustParseDateTime("2021-01-01T00:00"))
+	assert.False(t, ok)
+}
+
+func TestSparse(t *testing.T) {
+	// the fifth Monday in February is only on 29 February
+	s := MustParse("0 0 * 2 MON#5")
+	next, ok := s.Next(mustParseDateTime("2072-03-01T00:00"))
+	assert.True(t, ok)
+	assert.Equal(t, "2112-02-29T00:00:00", next.String())
+	prev, ok := s.Prev(mustParseDateTime("2112-02-01T00:00"))
+	assert.True(t, ok)
+	assert.Equal(t, "2072-02-29T00:00:00", prev.String())
+}
+
+func TestBetween(t *testing.T) {
+	s := MustParse("0,30 9 * * *")
+	times := s.Between(mustParseDateTime("2021-03-01T09:00"), mustParseDateTime("2021-03-02T09:30"))
+	var actual []string
+	for _, dt := range times {
+		actual = append(actual, dt.String())
+	}
+	assert.Equal(t, []string{"2021-03-01T09:00:00", "2021-03-01T09:30:00", "2021-03-02T09:00:00"}, actual)
+	assert.Equal(t, "0,30 9 * * *", s.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"x * * * *",
+		"* * L-31 * *",
+		"* * 32W * *",
+		"* * * * 5#6",
+		"* * * * XL",
+		"* * * * L",
+		"@fortnightly",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustParse("") })
+}
@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// ZonedSchedule is a schedule bound to a time zone.
+type ZonedSchedule struct {
+	s              *Schedule
+	loc            *time.Location
+	disambiguation civil.Disambiguation
+}
+
+// In binds the schedule to loc. Each time in the schedule is converted to an
+// instant with civil.DateTime.In using disambiguation, which determines what
+// happens to a time that is skipped or repeated by a daylight saving transition.
+// With civil.DisambiguateCompatible, a skipped time runs late by the length of
+// the gap and a repeated time runs once, at its first occurrence. With
+// civil.DisambiguateReject, skipped and repeated times do not run at all.
+// A repeated time never runs twice.
+func (s *Schedule) In(loc *time.Location, disambiguation civil.Disambiguation) *ZonedSchedule {
+	return &ZonedSchedule{s: s, loc: loc, disambiguation: disambiguation}
+}
+
+// Location returns the time zone of the schedule.
+func (z *ZonedSchedule) Location() *time.Location {
+	return z.loc
+}
+
+// Next returns the first instant in the schedule after t, or false if
+// there is none.
+func (z *ZonedSchedule) Next(t time.Time) (time.Time, bool) {
+	// A time shortly before t may resolve to an instant after t, if it is
+	// moved forward out of a gap, so the search starts early enough to find it.
+	dt := civil.DateTimeOf(t.In(z.loc)).Add(-z.shift(t) - time.Second)
+	limit := dt.AddDate(0, 0, maxDays)
+	var best time.Time
+	found := false
+	for {
+		var ok bool
+		if dt, ok = z.s.Next(dt); !ok || dt.After(limit) {
+			break
+		}
+		if found && dt.Sub(civil.DateTimeOf(best)) > z.shift(best) {
+			break
+		}
+		at, err := dt.In(z.loc, z.disambiguation)
+		if err != nil {
+			continue
+		}
+		if at.After(t) && (!found || at.Before(best)) {
+			best, found = at, true
+		}
+	}
+	return best, found
+}
+
+// Prev returns the last instant in the schedule before t, or false if
+// there is none.
+func (z *ZonedSchedule) Prev(t time.Time) (time.Time, bool) {
+	dt := civil.DateTimeOf(t.In(z.loc)).Add(z.shift(t) + time.Second)
+	limit := dt.AddDate(0, 0, -maxDays)
+	var best time.Time
+	found := false
+	for {
+		var ok bool
+		if dt, ok = z.s.Prev(dt); !ok || dt.Before(limit) {
+			break
+		}
+		if found && civil.DateTimeOf(best).Sub(dt) > z.shift(best) {
+			break
+		}
+		at, err := dt.In(z.loc, z.disambiguation)
+		if err != nil {
+			continue
+		}
+		if at.Before(t) && (!found || at.After(best)) {
+			best, found = at, true
+		}
+	}
+	return best, found
+}
+
+// Between returns the instants in the schedule from a up to, but not
+// including, b.
+func (z *ZonedSchedule) Between(a, b time.Time) []time.Time {
+	var times []time.Time
+	for t, ok := z.Next(a.Add(-time.Nanosecond)); ok && t.Before(b); t, ok = z.Next(t) {
+		times = append(times, t)
+	}
+	return times
+}
+
+// shift returns the size of any change in offset within a day of t, which
+// is the most that a civil date-time near t can move when it is resolved.
+func (z *ZonedSchedule) shift(t time.Time) time.Duration {
+	_, before := t.Add(-24 * time.Hour).In(z.loc).Zone()
+	_, after := t.Add(24 * time.Hour).In(z.loc).Zone()
+	if before > after {
+		before, after = after, before
+	}
+	return time.Duration(after-before) * time.Second
+}
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return loc
+}
+
+func TestZonedTransitions(t *testing.T) {
+	sydney := mustLoadLocation("Australia/Sydney")
+	// clocks turned forward from 02:00 to 03:00 on 3 October 2021
+	gapDay := time.Date(2021, 10, 3, 1, 0, 0, 0, sydney)
+	// clocks turned back from 03:00 to 02:00 on 4 April 2021
+	overlapDay := time.Date(2021, 4, 3, 14, 0, 0, 0, time.UTC).In(sydney)
+
+	testCases := []struct {
+		Start          time.Time
+		Disambiguation civil.Disambiguation
+		Expected       []string
+	}{
+		{gapDay, civil.DisambiguateCompatible, []string{"2021-10-03T03:30:00+11:00", "2021-10-04T02:30:00+11:00"}},
+		{gapDay, civil.DisambiguateEarlier, []string{"2021-10-03T01:30:00+10:00", "2021-10-04T02:30:00+11:00"}},
+		{gapDay, civil.DisambiguateLater, []string{"2021-10-03T03:30:00+11:00", "2021-10-04T02:30:00+11:00"}},
+		{gapDay, civil.DisambiguateReject, []string{"2021-10-04T02:30:00+11:00", "2021-10-05T02:30:00+11:00"}},
+		{overlapDay, civil.DisambiguateCompatible, []string{"2021-04-04T02:30:00+11:00", "2021-04-05T02:30:00+10:00"}},
+		{overlapDay, civil.DisambiguateEarlier, []string{"2021-04-04T02:30:00+11:00", "2021-04-05T02:30:00+10:00"}},
+		{overlapDay, civil.DisambiguateLater, []string{"2021-04-04T02:30:00+10:00", "2021-04-05T02:30:00+10:00"}},
+		{overlapDay, civil.DisambiguateReject, []string{"2021-04-05T02:30:00+10:00", "2021-04-06T02:30:00+10:00"}},
+	}
+	s := MustParse("30 2 * * *")
+	for i, tc := range testCases {
+		z := s.In(sydney, tc.Disambiguation)
+		assert.Equal(t, sydney, z.Location())
+		at := tc.Start
+		var actual []string
+		for range tc.Expected {
+			var ok bool
+			at, ok = z.Next(at)
+			assert.True(t, ok)
+			actual = append(actual, at.Format(time.RFC3339))
+		}
+		assert.Equal(t, tc.Expected, actual, "test case %d", i)
+
+		prev, ok := z.Prev(at)
+		assert.True(t, ok)
+		assert.Equal(t, tc.Expected[0], prev.Format(time.RFC3339), "test case %d", i)
+	}
+}
+
+func TestZonedBetween(t *testing.T) {
+	sydney := mustLoadLocation("Australia/Sydney")
+	z := MustParse("*/15 * * * *").In(sydney, civil.DisambiguateCompatible)
+
+	// times in the gap are moved forward, and times that resolve
+	// to the same instant run once
+	start := time.Date(2021, 10, 3, 1, 30, 0, 0, sydney)
+	end := time.Date(2021, 10, 3, 3, 30, 0, 0, sydney)
+	var actual []string
+	for _, at := range z.Between(start, end) {
+		actual = append(actual, at.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{
+		"2021-10-03T01:30:00+10:00",
+		"2021-10-03T01:45:00+10:00",
+		"2021-10-03T03:00:00+11:00",
+		"2021-10-03T03:15:00+11:00",
+	}, actual)
+
+	prev, ok := z.Prev(time.Date(2021, 10, 3, 3, 10, 0, 0, sydney))
+	assert.True(t, ok)
+	assert.Equal(t, "2021-10-03T03:00:00+11:00", prev.Format(time.RFC3339))
+}
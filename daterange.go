@@ -0,0 +1,83 @@
+package civil
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateRange represents the range of civil dates from Start to End,
+// inclusive of both. A range covering the whole of 2021 has a Start
+// of 2021-01-01 and an End of 2021-12-31.
+//
+// A DateRange whose End is before its Start is empty.
+type DateRange struct {
+	Start Date
+	End   Date
+}
+
+// DateRangeFor returns the range of dates from start to end inclusive.
+func DateRangeFor(start, end Date) DateRange {
+	return DateRange{Start: start, End: end}
+}
+
+// IsEmpty reports whether r contains no dates.
+func (r DateRange) IsEmpty() bool {
+	return r.End.Before(r.Start)
+}
+
+// Days returns the number of dates in r.
+func (r DateRange) Days() int {
+	if r.IsEmpty() {
+		return 0
+	}
+	return int(dayNumber(r.End)-dayNumber(r.Start)) + 1
+}
+
+// Contains reports whether d is within r.
+func (r DateRange) Contains(d Date) bool {
+	return !d.Before(r.Start) && !d.After(r.End)
+}
+
+// Overlaps reports whether r and s have at least one date in common.
+func (r DateRange) Overlaps(s DateRange) bool {
+	if r.IsEmpty() || s.IsEmpty() {
+		return false
+	}
+	return !r.Start.After(s.End) && !s.Start.After(r.End)
+}
+
+// Equal reports whether r and s have the same start and end.
+func (r DateRange) Equal(s DateRange) bool {
+	return r.Start.Equal(s.Start) && r.End.Equal(s.End)
+}
+
+// Dates returns each of the dates in r, in order.
+func (r DateRange) Dates() []Date {
+	n := r.Days()
+	if n == 0 {
+		return nil
+	}
+	dates := make([]Date, n)
+	start := dayNumber(r.Start)
+	for i := range dates {
+		dates[i] = dateForDayNumber(start + int64(i))
+	}
+	return dates
+}
+
+// String returns a string representation of r using the
+// ISO 8601 time interval format: yyyy-mm-dd/yyyy-mm-dd.
+func (r DateRange) String() string {
+	return fmt.Sprintf("%s/%s", r.Start, r.End)
+}
+
+// dayNumber returns the number of days between January 1, 1970 and d.
+func dayNumber(d Date) int64 {
+	// d.t is always midnight UTC, so the division is exact
+	return d.t.Unix() / secondsPerDay
+}
+
+// dateForDayNumber returns the date that is n days after January 1, 1970.
+func dateForDayNumber(n int64) Date {
+	return Date{t: time.Unix(n*secondsPerDay, 0).UTC()}
+}
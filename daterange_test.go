@@ -0,0 +1,50 @@
+package civil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRange(t *testing.T) {
+	assert := assert.New(t)
+	r := DateRangeFor(mustParseDate("2021-02-26"), mustParseDate("2021-03-02"))
+
+	assert.False(r.IsEmpty())
+	assert.Equal(5, r.Days())
+	assert.Equal("2021-02-26/2021-03-02", r.String())
+	assert.True(r.Contains(mustParseDate("2021-02-26")))
+	assert.True(r.Contains(mustParseDate("2021-03-02")))
+	assert.False(r.Contains(mustParseDate("2021-03-03")))
+	assert.False(r.Contains(mustParseDate("2021-02-25")))
+
+	dates := r.Dates()
+	assert.Len(dates, 5)
+	assert.Equal("2021-02-28", dates[2].String())
+	assert.Equal("2021-03-01", dates[3].String())
+
+	assert.True(r.Overlaps(DateRangeFor(mustParseDate("2021-03-02"), mustParseDate("2021-03-05"))))
+	assert.False(r.Overlaps(DateRangeFor(mustParseDate("2021-03-03"), mustParseDate("2021-03-05"))))
+
+	empty := DateRangeFor(r.End, r.Start)
+	assert.True(empty.IsEmpty())
+	assert.Equal(0, empty.Days())
+	assert.Nil(empty.Dates())
+	assert.False(empty.Overlaps(r))
+
+	single := DateRangeFor(r.Start, r.Start)
+	assert.False(single.IsEmpty())
+	assert.Equal(1, single.Days())
+}
+
+func TestDayNumber(t *testing.T) {
+	assert := assert.New(t)
+	for _, s := range []string{"1970-01-01", "1969-12-31", "2021-03-01", "-0044-03-15", "0001-01-01", "9999-12-31"} {
+		d := mustParseDate(s)
+		n := dayNumber(d)
+		assert.True(d.Equal(dateForDayNumber(n)), s)
+		assert.Equal(n+1, dayNumber(d.AddDate(0, 0, 1)), s)
+	}
+	assert.Equal(int64(0), dayNumber(DateFor(1970, 1, 1)))
+	assert.Equal(int64(-1), dayNumber(DateFor(1969, 12, 31)))
+}
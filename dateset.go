@@ -0,0 +1,427 @@
+package civil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+var errInvalidDateSet = errors.New("invalid civil.DateSet encoding")
+
+const dateSetVersion = 1
+
+// DateSet is a set of civil dates. It is stored as a bitmap with one
+// bit for each day between the earliest and latest dates in the set,
+// so sets of dates that are close together are very compact.
+//
+// The zero value for DateSet is an empty set ready to use.
+// A DateSet must not be modified concurrently with any other use.
+type DateSet struct {
+	base  int64 // day number of bit 0 of words[0], a multiple of 64
+	words []uint64
+}
+
+// NewDateSet returns a set containing the dates.
+func NewDateSet(dates ...Date) *DateSet {
+	s := &DateSet{}
+	for _, d := range dates {
+		s.Add(d)
+	}
+	return s
+}
+
+// Add adds d to the set.
+func (s *DateSet) Add(d Date) {
+	n := dayNumber(d)
+	s.grow(n)
+	i, bit := s.position(n)
+	s.words[i] |= bit
+}
+
+// AddRange adds every date in r to the set.
+func (s *DateSet) AddRange(r DateRange) {
+	if r.IsEmpty() {
+		return
+	}
+	first, last := dayNumber(r.Start), dayNumber(r.End)
+	s.grow(first)
+	s.grow(last)
+	for n := first; n <= last; n++ {
+		i, bit := s.position(n)
+		s.words[i] |= bit
+	}
+}
+
+// Remove removes d from the set.
+func (s *DateSet) Remove(d Date) {
+	n := dayNumber(d)
+	if !s.inBounds(n) {
+		return
+	}
+	i, bit := s.position(n)
+	s.words[i] &^= bit
+}
+
+// Has reports whether d is in the set.
+func (s *DateSet) Has(d Date) bool {
+	n := dayNumber(d)
+	if !s.inBounds(n) {
+		return false
+	}
+	i, bit := s.position(n)
+	return s.words[i]&bit != 0
+}
+
+// Cardinality returns the number of dates in the set.
+func (s *DateSet) Cardinality() int {
+	count := 0
+	for _, w := range s.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// IsEmpty reports whether the set contains no dates.
+func (s *DateSet) IsEmpty() bool {
+	for _, w := range s.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether s and t contain the same dates.
+func (s *DateSet) Equal(t *DateSet) bool {
+	lo, hi := s.base, s.end()
+	if t.base < lo {
+		lo = t.base
+	}
+	if e := t.end(); e > hi {
+		hi = e
+	}
+	for n := lo; n < hi; n += 64 {
+		if s.word(n) != t.word(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the set.
+func (s *DateSet) Clone() *DateSet {
+	c := &DateSet{base: s.base, words: make([]uint64, len(s.words))}
+	copy(c.words, s.words)
+	return c
+}
+
+// Union returns a new set containing the dates that are in s or t.
+func (s *DateSet) Union(t *DateSet) *DateSet {
+	return s.combine(t, func(a, b uint64) uint64 { return a | b })
+}
+
+// Intersect returns a new set containing the dates that are in both s and t.
+func (s *DateSet) Intersect(t *DateSet) *DateSet {
+	return s.combine(t, func(a, b uint64) uint64 { return a & b })
+}
+
+// Difference returns a new set containing the dates that are in s but not in t.
+func (s *DateSet) Difference(t *DateSet) *DateSet {
+	return s.combine(t, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// Each calls fn for each date in the set, in order, until fn returns false.
+func (s *DateSet) Each(fn func(d Date) bool) {
+	for i, w := range s.words {
+		for w != 0 {
+			b := bits.TrailingZeros64(w)
+			if !fn(dateForDayNumber(s.base + int64(i*64+b))) {
+				return
+			}
+			w &^= 1 << uint(b)
+		}
+	}
+}
+
+// Dates returns the dates in the set, in order.
+func (s *DateSet) Dates() []Date {
+	var dates []Date
+	s.Each(func(d Date) bool {
+		dates = append(dates, d)
+		return true
+	})
+	return dates
+}
+
+// Missing returns the dates in r that are not in the set, in order.
+func (s *DateSet) Missing(r DateRange) []Date {
+	var dates []Date
+	if r.IsEmpty() {
+		return dates
+	}
+	for n, last := dayNumber(r.Start), dayNumber(r.End); n <= last; n++ {
+		if s.inBounds(n) {
+			i, bit := s.position(n)
+			if s.words[i]&bit != 0 {
+				continue
+			}
+		}
+		dates = append(dates, dateForDayNumber(n))
+	}
+	return dates
+}
+
+// Streaks returns the runs of consecutive dates in the set, in order.
+func (s *DateSet) Streaks() []DateRange {
+	var streaks []DateRange
+	s.runs(func(first, last int64) {
+		streaks = append(streaks, DateRange{
+			Start: dateForDayNumber(first),
+			End:   dateForDayNumber(last),
+		})
+	})
+	return streaks
+}
+
+// LongestStreak returns the longest run of consecutive dates in the set.
+// If there is more than one longest run, the earliest is returned.
+// If the set is empty, the result is an empty DateRange.
+func (s *DateSet) LongestStreak() DateRange {
+	longest := DateRange{End: Date{}.AddDate(0, 0, -1)}
+	for _, r := range s.Streaks() {
+		if r.Days() > longest.Days() {
+			longest = r
+		}
+	}
+	return longest
+}
+
+// String returns a string representation of the set, which is a list
+// of dates and ranges of consecutive dates separated by commas.
+func (s DateSet) String() string {
+	return strings.Join(s.runStrings(), ",")
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s DateSet) MarshalBinary() ([]byte, error) {
+	lo, hi := s.trimmed()
+	buf := make([]byte, 1+2*binary.MaxVarintLen64+8*(hi-lo))
+	buf[0] = dateSetVersion
+	n := 1
+	n += binary.PutVarint(buf[n:], (s.base/64)+int64(lo))
+	n += binary.PutUvarint(buf[n:], uint64(hi-lo))
+	for _, w := range s.words[lo:hi] {
+		binary.LittleEndian.PutUint64(buf[n:], w)
+		n += 8
+	}
+	return buf[:n], nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (s *DateSet) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != dateSetVersion {
+		return errInvalidDateSet
+	}
+	data = data[1:]
+	base, n := binary.Varint(data)
+	if n <= 0 {
+		return errInvalidDateSet
+	}
+	data = data[n:]
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errInvalidDateSet
+	}
+	// check the count before multiplying, which could overflow
+	if size := uint64(len(data) - n); count > size/8 || size != count*8 {
+		return errInvalidDateSet
+	}
+	data = data[n:]
+	words := make([]uint64, count)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	s.base = base * 64
+	s.words = words
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The set is
+// a JSON array of strings, each of which is either a single date
+// or a range of consecutive dates in ISO 8601 interval format
+// (yyyy-mm-dd/yyyy-mm-dd).
+func (s DateSet) MarshalJSON() ([]byte, error) {
+	runs := s.runStrings()
+	if runs == nil {
+		runs = []string{}
+	}
+	return json.Marshal(runs)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *DateSet) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullText) {
+		*s = DateSet{}
+		return nil
+	}
+	var runs []string
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return err
+	}
+	var set DateSet
+	for _, run := range runs {
+		parts := strings.Split(run, "/")
+		if len(parts) > 2 {
+			return errInvalidDateFormat
+		}
+		start, err := ParseDate(parts[0])
+		if err != nil {
+			return err
+		}
+		end := start
+		if len(parts) == 2 {
+			if end, err = ParseDate(parts[1]); err != nil {
+				return err
+			}
+			if end.Before(start) {
+				return errInvalidDateFormat
+			}
+		}
+		set.AddRange(DateRange{Start: start, End: end})
+	}
+	*s = set
+	return nil
+}
+
+// runs calls fn with the first and last day numbers of each
+// run of consecutive dates in the set.
+func (s *DateSet) runs(fn func(first, last int64)) {
+	started := false
+	var first, last int64
+	s.Each(func(d Date) bool {
+		n := dayNumber(d)
+		if started && n == last+1 {
+			last = n
+			return true
+		}
+		if started {
+			fn(first, last)
+		}
+		started, first, last = true, n, n
+		return true
+	})
+	if started {
+		fn(first, last)
+	}
+}
+
+func (s *DateSet) runStrings() []string {
+	var runs []string
+	s.runs(func(first, last int64) {
+		if first == last {
+			runs = append(runs, toDateString(dateForDayNumber(first)))
+			return
+		}
+		runs = append(runs, DateRange{
+			Start: dateForDayNumber(first),
+			End:   dateForDayNumber(last),
+		}.String())
+	})
+	return runs
+}
+
+// combine returns a new set whose words are the result of
+// calling op on the corresponding words in s and t.
+func (s *DateSet) combine(t *DateSet, op func(a, b uint64) uint64) *DateSet {
+	lo, hi := s.base, s.end()
+	if len(s.words) == 0 {
+		lo, hi = t.base, t.end()
+	} else if len(t.words) > 0 {
+		if t.base < lo {
+			lo = t.base
+		}
+		if e := t.end(); e > hi {
+			hi = e
+		}
+	}
+	result := &DateSet{base: lo}
+	if hi > lo {
+		result.words = make([]uint64, (hi-lo)/64)
+	}
+	for i := range result.words {
+		n := lo + int64(i)*64
+		result.words[i] = op(s.word(n), t.word(n))
+	}
+	return result
+}
+
+// word returns the word whose first bit represents day number n,
+// which must be a multiple of 64.
+func (s *DateSet) word(n int64) uint64 {
+	if n < s.base || n >= s.end() {
+		return 0
+	}
+	return s.words[(n-s.base)/64]
+}
+
+// end returns the day number just past the last bit in the bitmap.
+func (s *DateSet) end() int64 {
+	return s.base + int64(len(s.words))*64
+}
+
+func (s *DateSet) inBounds(n int64) bool {
+	return n >= s.base && n < s.end()
+}
+
+// position returns the index of the word and the bit
+// within that word representing day number n.
+func (s *DateSet) position(n int64) (int, uint64) {
+	offset := n - s.base
+	return int(offset / 64), 1 << uint(offset%64)
+}
+
+// grow extends the bitmap so that it includes day number n.
+func (s *DateSet) grow(n int64) {
+	wordBase := floorDiv(n, 64) * 64
+	if len(s.words) == 0 {
+		s.base = wordBase
+		s.words = make([]uint64, 1)
+		return
+	}
+	if wordBase < s.base {
+		extra := int((s.base - wordBase) / 64)
+		words := make([]uint64, extra+len(s.words))
+		copy(words[extra:], s.words)
+		s.base = wordBase
+		s.words = words
+		return
+	}
+	for n >= s.end() {
+		s.words = append(s.words, 0)
+	}
+}
+
+// trimmed returns the range of words that excludes leading
+// and trailing zero words.
+func (s *DateSet) trimmed() (lo, hi int) {
+	lo, hi = 0, len(s.words)
+	for lo < hi && s.words[lo] == 0 {
+		lo++
+	}
+	for hi > lo && s.words[hi-1] == 0 {
+		hi--
+	}
+	return lo, hi
+}
+
+// floorDiv returns a/b rounded towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
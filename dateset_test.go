@@ -0,0 +1,167 @@
+package civil
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dateStrings(dates []Date) []string {
+	var s []string
+	for _, d := range dates {
+		s = append(s, d.String())
+	}
+	return s
+}
+
+func TestDateSet(t *testing.T) {
+	assert := assert.New(t)
+	var s DateSet
+	assert.True(s.IsEmpty())
+	assert.False(s.Has(mustParseDate("2021-03-01")))
+
+	for _, text := range []string{"2021-03-05", "2021-03-01", "2021-03-02", "2021-03-03", "2020-12-31", "2021-06-30"} {
+		s.Add(mustParseDate(text))
+	}
+	assert.False(s.IsEmpty())
+	assert.Equal(6, s.Cardinality())
+	assert.True(s.Has(mustParseDate("2021-03-02")))
+	assert.False(s.Has(mustParseDate("2021-03-04")))
+	assert.Equal([]string{"2020-12-31", "2021-03-01", "2021-03-02", "2021-03-03", "2021-03-05", "2021-06-30"}, dateStrings(s.Dates()))
+
+	s.Remove(mustParseDate("2021-03-02"))
+	s.Remove(mustParseDate("1999-01-01"))
+	assert.Equal(5, s.Cardinality())
+	assert.False(s.Has(mustParseDate("2021-03-02")))
+	assert.Equal("2020-12-31,2021-03-01,2021-03-03,2021-03-05,2021-06-30", s.String())
+
+	// Each stops when fn returns false
+	var first []Date
+	s.Each(func(d Date) bool {
+		first = append(first, d)
+		return len(first) < 2
+	})
+	assert.Equal([]string{"2020-12-31", "2021-03-01"}, dateStrings(first))
+}
+
+func TestDateSetNegativeDays(t *testing.T) {
+	assert := assert.New(t)
+	s := NewDateSet(mustParseDate("1969-12-31"), mustParseDate("1970-01-01"), mustParseDate("-0001-06-01"))
+	assert.Equal(3, s.Cardinality())
+	assert.True(s.Has(mustParseDate("1969-12-31")))
+	assert.True(s.Has(mustParseDate("-0001-06-01")))
+	assert.False(s.Has(mustParseDate("1969-12-30")))
+	assert.Equal([]string{"-0001-06-01", "1969-12-31", "1970-01-01"}, dateStrings(s.Dates()))
+}
+
+func TestDateSetOperations(t *testing.T) {
+	assert := assert.New(t)
+	a := NewDateSet()
+	a.AddRange(DateRangeFor(mustParseDate("2021-03-01"), mustParseDate("2021-03-10")))
+	b := NewDateSet()
+	b.AddRange(DateRangeFor(mustParseDate("2021-03-08"), mustParseDate("2021-05-01")))
+
+	assert.Equal("2021-03-01/2021-05-01", a.Union(b).String())
+	assert.Equal("2021-03-08/2021-03-10", a.Intersect(b).String())
+	assert.Equal("2021-03-01/2021-03-07", a.Difference(b).String())
+	assert.Equal("2021-03-11/2021-05-01", b.Difference(a).String())
+	assert.Equal("2021-03-01/2021-03-10", a.Union(NewDateSet()).String())
+	assert.Equal("2021-03-01/2021-03-10", NewDateSet().Union(a).String())
+	assert.True(a.Intersect(NewDateSet()).IsEmpty())
+
+	c := a.Clone()
+	assert.True(a.Equal(c))
+	c.Remove(mustParseDate("2021-03-05"))
+	assert.False(a.Equal(c))
+	assert.True(a.Has(mustParseDate("2021-03-05")))
+	assert.True(NewDateSet().Equal(&DateSet{}))
+}
+
+func TestDateSetMissingAndStreaks(t *testing.T) {
+	assert := assert.New(t)
+	s := NewDateSet()
+	s.AddRange(DateRangeFor(mustParseDate("2021-01-01"), mustParseDate("2021-01-03")))
+	s.AddRange(DateRangeFor(mustParseDate("2021-01-06"), mustParseDate("2021-01-12")))
+	s.Add(mustParseDate("2021-01-14"))
+
+	missing := s.Missing(DateRangeFor(mustParseDate("2020-12-30"), mustParseDate("2021-01-15")))
+	assert.Equal([]string{"2020-12-30", "2020-12-31", "2021-01-04", "2021-01-05", "2021-01-13", "2021-01-15"}, dateStrings(missing))
+
+	streaks := s.Streaks()
+	assert.Len(streaks, 3)
+	assert.Equal("2021-01-01/2021-01-03", streaks[0].String())
+	assert.Equal("2021-01-06/2021-01-12", streaks[1].String())
+	assert.Equal("2021-01-14/2021-01-14", streaks[2].String())
+	assert.Equal("2021-01-06/2021-01-12", s.LongestStreak().String())
+	assert.True(NewDateSet().LongestStreak().IsEmpty())
+}
+
+func TestDateSetBinary(t *testing.T) {
+	assert := assert.New(t)
+	s := NewDateSet()
+	s.AddRange(DateRangeFor(mustParseDate("2021-01-01"), mustParseDate("2021-12-31")))
+	s.Remove(mustParseDate("2021-07-04"))
+	s.Add(mustParseDate("1969-07-20"))
+	s.Remove(mustParseDate("1969-07-20"))
+
+	data, err := s.MarshalBinary()
+	assert.NoError(err)
+	// 365 days fit in 6 words, plus a small header
+	assert.True(len(data) <= 6*8+8, "%d bytes", len(data))
+
+	var s2 DateSet
+	assert.NoError(s2.UnmarshalBinary(data))
+	assert.True(s.Equal(&s2))
+	assert.Equal(364, s2.Cardinality())
+
+	var empty DateSet
+	data, err = empty.MarshalBinary()
+	assert.NoError(err)
+	assert.NoError(s2.UnmarshalBinary(data))
+	assert.True(s2.IsEmpty())
+
+	assert.Error(s2.UnmarshalBinary(nil))
+	assert.Error(s2.UnmarshalBinary([]byte{9, 0, 0}))
+	assert.Error(s2.UnmarshalBinary([]byte{dateSetVersion, 2, 1, 0}))
+
+	// counts where count*8 overflows to the number of bytes remaining
+	for _, extra := range []int{0, 8} {
+		data := []byte{dateSetVersion, 0}
+		data = append(data, make([]byte, binary.MaxVarintLen64)...)
+		n := binary.PutUvarint(data[2:], 1<<61+uint64(extra/8))
+		data = append(data[:2+n], make([]byte, extra)...)
+		assert.Error(s2.UnmarshalBinary(data), "%x", data)
+	}
+}
+
+func TestDateSetJSON(t *testing.T) {
+	assert := assert.New(t)
+	s := NewDateSet(mustParseDate("2021-03-05"))
+	s.AddRange(DateRangeFor(mustParseDate("2021-03-01"), mustParseDate("2021-03-03")))
+
+	data, err := json.Marshal(s)
+	assert.NoError(err)
+	assert.Equal(`["2021-03-01/2021-03-03","2021-03-05"]`, string(data))
+
+	var s2 DateSet
+	assert.NoError(json.Unmarshal(data, &s2))
+	assert.True(s.Equal(&s2))
+
+	data, err = json.Marshal(NewDateSet())
+	assert.NoError(err)
+	assert.Equal(`[]`, string(data))
+
+	// a set held by value is marshaled the same way
+	data, err = json.Marshal(struct{ S DateSet }{*s})
+	assert.NoError(err)
+	assert.Equal(`{"S":["2021-03-01/2021-03-03","2021-03-05"]}`, string(data))
+
+	assert.NoError(json.Unmarshal([]byte(`null`), &s2))
+	assert.True(s2.IsEmpty())
+	assert.Error(json.Unmarshal([]byte(`["2021-03-01/2021-03-02/2021-03-03"]`), &s2))
+	assert.Error(json.Unmarshal([]byte(`["xxx"]`), &s2))
+	assert.Error(json.Unmarshal([]byte(`["2021-03-05/2021-03-01"]`), &s2))
+	assert.Error(json.Unmarshal([]byte(`{}`), &s2))
+}
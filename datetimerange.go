@@ -0,0 +1,85 @@
+package civil
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateTimeRange represents the half-open range of civil date-times
+// [Start, End). The range includes Start but does not include End,
+// so adjacent ranges can share a boundary without overlapping.
+//
+// A DateTimeRange whose End is not after its Start is empty.
+type DateTimeRange struct {
+	Start DateTime
+	End   DateTime
+}
+
+// DateTimeRangeFor returns the range [start, end).
+func DateTimeRangeFor(start, end DateTime) DateTimeRange {
+	return DateTimeRange{Start: start, End: end}
+}
+
+// IsEmpty reports whether r contains no date-times.
+func (r DateTimeRange) IsEmpty() bool {
+	return !r.End.After(r.Start)
+}
+
+// Duration returns the length of r. The duration of an empty
+// range is zero.
+func (r DateTimeRange) Duration() time.Duration {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+// Contains reports whether dt is within r.
+func (r DateTimeRange) Contains(dt DateTime) bool {
+	return !dt.Before(r.Start) && dt.Before(r.End)
+}
+
+// ContainsRange reports whether every date-time in s is also in r.
+// An empty range is contained by any non-empty range that includes
+// its start.
+func (r DateTimeRange) ContainsRange(s DateTimeRange) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	return !s.Start.Before(r.Start) && !s.End.After(r.End)
+}
+
+// Overlaps reports whether r and s have at least one date-time in common.
+func (r DateTimeRange) Overlaps(s DateTimeRange) bool {
+	if r.IsEmpty() || s.IsEmpty() {
+		return false
+	}
+	return r.Start.Before(s.End) && s.Start.Before(r.End)
+}
+
+// Intersect returns the date-times common to r and s. If r and s
+// do not overlap, the result is empty.
+func (r DateTimeRange) Intersect(s DateTimeRange) DateTimeRange {
+	start, end := r.Start, r.End
+	if s.Start.After(start) {
+		start = s.Start
+	}
+	if s.End.Before(end) {
+		end = s.End
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return DateTimeRange{Start: start, End: end}
+}
+
+// Equal reports whether r and s have the same start and end.
+func (r DateTimeRange) Equal(s DateTimeRange) bool {
+	return r.Start.Equal(s.Start) && r.End.Equal(s.End)
+}
+
+// String returns a string representation of r using the
+// ISO 8601 time interval format: yyyy-mm-ddTHH:MM:SS/yyyy-mm-ddTHH:MM:SS.
+func (r DateTimeRange) String() string {
+	return fmt.Sprintf("%s/%s", r.Start, r.End)
+}
@@ -0,0 +1,77 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateTimeRange(t *testing.T) {
+	assert := assert.New(t)
+	r := DateTimeRangeFor(mustParseDateTime("2021-03-01T22:00"), mustParseDateTime("2021-03-02T06:00"))
+
+	assert.False(r.IsEmpty())
+	assert.Equal(8*time.Hour, r.Duration())
+	assert.Equal("2021-03-01T22:00:00/2021-03-02T06:00:00", r.String())
+
+	assert.True(r.Contains(mustParseDateTime("2021-03-01T22:00")))
+	assert.True(r.Contains(mustParseDateTime("2021-03-02T05:59:59")))
+	assert.False(r.Contains(mustParseDateTime("2021-03-02T06:00")))
+	assert.False(r.Contains(mustParseDateTime("2021-03-01T21:59:59")))
+
+	empty := DateTimeRangeFor(r.End, r.Start)
+	assert.True(empty.IsEmpty())
+	assert.Equal(time.Duration(0), empty.Duration())
+	assert.False(empty.Contains(r.Start))
+}
+
+func TestDateTimeRangeOverlaps(t *testing.T) {
+	assert := assert.New(t)
+	rangeFor := func(start, end string) DateTimeRange {
+		return DateTimeRangeFor(mustParseDateTime(start), mustParseDateTime(end))
+	}
+	testCases := []struct {
+		R1, R2        DateTimeRange
+		Overlaps      bool
+		Contains      bool
+		Intersect     DateTimeRange
+		IntersectNone bool
+	}{
+		{
+			R1:        rangeFor("2021-03-01T08:00", "2021-03-01T12:00"),
+			R2:        rangeFor("2021-03-01T10:00", "2021-03-01T14:00"),
+			Overlaps:  true,
+			Intersect: rangeFor("2021-03-01T10:00", "2021-03-01T12:00"),
+		},
+		{
+			R1:        rangeFor("2021-03-01T08:00", "2021-03-01T12:00"),
+			R2:        rangeFor("2021-03-01T09:00", "2021-03-01T10:00"),
+			Overlaps:  true,
+			Contains:  true,
+			Intersect: rangeFor("2021-03-01T09:00", "2021-03-01T10:00"),
+		},
+		{
+			R1:            rangeFor("2021-03-01T08:00", "2021-03-01T12:00"),
+			R2:            rangeFor("2021-03-01T12:00", "2021-03-01T14:00"),
+			IntersectNone: true,
+		},
+		{
+			R1:            rangeFor("2021-03-01T08:00", "2021-03-01T12:00"),
+			R2:            rangeFor("2021-03-02T08:00", "2021-03-02T12:00"),
+			IntersectNone: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Overlaps, tc.R1.Overlaps(tc.R2), tc.R1.String()+" "+tc.R2.String())
+		assert.Equal(tc.Overlaps, tc.R2.Overlaps(tc.R1), tc.R2.String()+" "+tc.R1.String())
+		assert.Equal(tc.Contains, tc.R1.ContainsRange(tc.R2), tc.R1.String()+" "+tc.R2.String())
+		intersect := tc.R1.Intersect(tc.R2)
+		if tc.IntersectNone {
+			assert.True(intersect.IsEmpty())
+		} else {
+			assert.True(tc.Intersect.Equal(intersect), intersect.String())
+		}
+	}
+}
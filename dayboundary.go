@@ -0,0 +1,41 @@
+package civil
+
+import "time"
+
+// StartIn returns the first instant of the civil date d in loc.
+//
+// This is usually midnight, but in some zones, such as America/Santiago,
+// clocks are turned forward at midnight and the day starts at 01:00.
+// If clocks are turned back at midnight, the day starts at the first
+// occurrence of midnight.
+func (d Date) StartIn(loc *time.Location) time.Time {
+	t, _ := d.midnight().In(loc, DisambiguateCompatible)
+	return t
+}
+
+// EndIn returns the first instant after the civil date d in loc, which is
+// the start of the following day. The instants of d are those from StartIn
+// up to, but not including, EndIn.
+func (d Date) EndIn(loc *time.Location) time.Time {
+	return d.AddDate(0, 0, 1).StartIn(loc)
+}
+
+// InstantRange returns the half-open range of instants [start, end) that
+// make up the civil date d in loc. On days when clocks are changed, the
+// range is not 24 hours long.
+func (d Date) InstantRange(loc *time.Location) (start, end time.Time) {
+	return d.StartIn(loc), d.EndIn(loc)
+}
+
+// InstantRange returns the half-open range of instants [start, end) that
+// make up the civil dates in r in loc. The bounds are suitable for a
+// database query of the form
+//  t >= start AND t < end
+// If r is empty, start and end are equal.
+func (r DateRange) InstantRange(loc *time.Location) (start, end time.Time) {
+	start = r.Start.StartIn(loc)
+	if r.IsEmpty() {
+		return start, start
+	}
+	return start, r.End.EndIn(loc)
+}
@@ -0,0 +1,59 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateInstantRange(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	santiago := mustLoadLocation("America/Santiago")
+	testCases := []struct {
+		Date     string
+		Location *time.Location
+		Start    string
+		End      string
+		Hours    time.Duration
+	}{
+		{"2021-03-01", sydney, "2021-03-01T00:00:00+11:00", "2021-03-02T00:00:00+11:00", 24},
+		{"2021-10-03", sydney, "2021-10-03T00:00:00+10:00", "2021-10-04T00:00:00+11:00", 23},
+		{"2021-04-04", sydney, "2021-04-04T00:00:00+11:00", "2021-04-05T00:00:00+10:00", 25},
+		// midnight does not occur in Santiago on 2021-09-05
+		{"2021-09-05", santiago, "2021-09-05T01:00:00-03:00", "2021-09-06T00:00:00-03:00", 23},
+		{"2021-09-04", santiago, "2021-09-04T00:00:00-04:00", "2021-09-05T01:00:00-03:00", 24},
+		// clocks go back from midnight to 23:00 in Santiago on 2021-04-04
+		{"2021-04-03", santiago, "2021-04-03T00:00:00-03:00", "2021-04-04T00:00:00-04:00", 25},
+		{"2021-04-04", santiago, "2021-04-04T00:00:00-04:00", "2021-04-05T00:00:00-04:00", 24},
+		{"2021-03-01", time.UTC, "2021-03-01T00:00:00Z", "2021-03-02T00:00:00Z", 24},
+	}
+
+	for _, tc := range testCases {
+		d := mustParseDate(tc.Date)
+		start, end := d.InstantRange(tc.Location)
+		assert.Equal(tc.Start, start.Format(time.RFC3339), tc.Date)
+		assert.Equal(tc.End, end.Format(time.RFC3339), tc.Date)
+		assert.Equal(tc.Hours*time.Hour, end.Sub(start), tc.Date)
+		assert.True(start.Equal(d.StartIn(tc.Location)))
+		assert.True(end.Equal(d.EndIn(tc.Location)))
+		assert.True(DateOf(start).Equal(d), tc.Date)
+		assert.True(DateOf(start.Add(-time.Second)).Before(d), tc.Date)
+	}
+}
+
+func TestDateRangeInstantRange(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+
+	r := DateRangeFor(mustParseDate("2021-10-01"), mustParseDate("2021-10-03"))
+	start, end := r.InstantRange(sydney)
+	assert.Equal("2021-10-01T00:00:00+10:00", start.Format(time.RFC3339))
+	assert.Equal("2021-10-04T00:00:00+11:00", end.Format(time.RFC3339))
+	assert.Equal(71*time.Hour, end.Sub(start))
+
+	empty := DateRangeFor(mustParseDate("2021-10-03"), mustParseDate("2021-10-01"))
+	start, end = empty.InstantRange(sydney)
+	assert.True(start.Equal(end))
+}
@@ -0,0 +1,337 @@
+// Package daycount calculates year fractions between dates using the day
+// count conventions of financial markets, such as ACT/360 and 30/360.
+//
+// The conventions follow the definitions in the 2006 ISDA Definitions,
+// and the ICMA rules for ACT/ACT ICMA.
+package daycount
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Convention is a day count convention.
+//
+// The conventions in this package are safe for concurrent use by multiple
+// goroutines, provided that any BusinessCalendar they use is.
+type Convention interface {
+	fmt.Stringer
+	dayCount(start, end civil.Date) int
+	yearFraction(start, end civil.Date) float64
+}
+
+// DayCount returns the number of days from start to end according to the
+// convention. It is negative if end is before start.
+func DayCount(start, end civil.Date, c Convention) int {
+	if end.Before(start) {
+		return -c.dayCount(end, start)
+	}
+	return c.dayCount(start, end)
+}
+
+// YearFraction returns the fraction of a year from start to end according to
+// the convention. It is negative if end is before start.
+func YearFraction(start, end civil.Date, c Convention) float64 {
+	if end.Before(start) {
+		return -c.yearFraction(end, start)
+	}
+	return c.yearFraction(start, end)
+}
+
+var (
+	// Act360 is ACT/360, which is the actual number of days divided by 360.
+	Act360 Convention = actual{name: "ACT/360", basis: 360}
+
+	// Act365Fixed is ACT/365F, which is the actual number of days divided by 365.
+	Act365Fixed Convention = actual{name: "ACT/365F", basis: 365}
+
+	// NL365 is NL/365, which is the actual number of days, not counting
+	// 29 February, divided by 365.
+	NL365 Convention = actual{name: "NL/365", basis: 365, noLeap: true}
+
+	// ActActISDA is ACT/ACT ISDA, which is the actual number of days in a
+	// leap year divided by 366, plus the actual number of days in a
+	// non-leap year divided by 365.
+	ActActISDA Convention = actActISDA{}
+
+	// Thirty360Bond is 30/360, also known as Bond Basis, as defined in
+	// the 2006 ISDA Definitions. A day 31 is treated as day 30, except
+	// that the end date is only adjusted if the start date is day 30 or 31.
+	Thirty360Bond Convention = thirty360{name: "30/360"}
+
+	// Thirty360US is 30/360 US, which is Bond Basis with additional rules for
+	// the last day of February, as used for US corporate and municipal bonds.
+	Thirty360US Convention = thirty360{name: "30/360 US", february: true}
+
+	// Thirty360E is 30E/360, also known as Eurobond Basis, where a day 31 is
+	// always treated as day 30.
+	Thirty360E Convention = thirty360{name: "30E/360", european: true}
+)
+
+// days returns the actual number of days from start to end.
+func days(start, end civil.Date) int {
+	return int((end.Unix() - start.Unix()) / (24 * 60 * 60))
+}
+
+type actual struct {
+	name   string
+	basis  float64
+	noLeap bool
+}
+
+func (c actual) String() string {
+	return c.name
+}
+
+func (c actual) dayCount(start, end civil.Date) int {
+	n := days(start, end)
+	if c.noLeap {
+		// 29 February is not counted if it is after start and not after end
+		for year := start.Year(); year <= end.Year(); year++ {
+			if leap := civil.DateFor(year, time.February, 29); leap.Month() == time.February &&
+				leap.After(start) && !leap.After(end) {
+				n--
+			}
+		}
+	}
+	return n
+}
+
+func (c actual) yearFraction(start, end civil.Date) float64 {
+	return float64(c.dayCount(start, end)) / c.basis
+}
+
+type actActISDA struct{}
+
+func (actActISDA) String() string {
+	return "ACT/ACT ISDA"
+}
+
+func (actActISDA) dayCount(start, end civil.Date) int {
+	return days(start, end)
+}
+
+func (actActISDA) yearFraction(start, end civil.Date) float64 {
+	var fraction float64
+	for from := start; from.Before(end); {
+		year := from.Year()
+		next := civil.DateFor(year+1, time.January, 1)
+		to := next
+		if end.Before(to) {
+			to = end
+		}
+		fraction += float64(days(from, to)) / float64(days(civil.DateFor(year, time.January, 1), next))
+		from = to
+	}
+	return fraction
+}
+
+// ActActICMA is ACT/ACT ICMA, which is the actual number of days divided by
+// the actual number of days in the coupon period multiplied by the number of
+// coupon periods in a year. For a stub period, notional coupon periods are
+// generated by rolling from a regular coupon date.
+//
+// The zero value has annual coupon periods, with the end date as the
+// regular coupon date.
+type ActActICMA struct {
+	months  int // length of a coupon period, or zero for annual
+	regular civil.Date
+}
+
+var errICMAFrequency = errors.New("daycount: ACT/ACT ICMA frequency must be a positive number of months")
+
+// NewActActICMA returns the ACT/ACT ICMA convention for coupon periods of
+// length frequency, which must be a whole number of months, such as
+// civil.PeriodOf(0, 6, 0) for semi-annual.
+//
+// Notional coupon periods are generated from the regular coupon date.
+// If it is the last day of a month, every notional coupon date is the
+// last day of a month. If regular is zero, the end date is used, which
+// is correct for a regular coupon period or an initial stub.
+func NewActActICMA(frequency civil.Period, regular civil.Date) (ActActICMA, error) {
+	months := frequency.Years*12 + frequency.Months
+	if months <= 0 || frequency.Days != 0 {
+		return ActActICMA{}, errICMAFrequency
+	}
+	return ActActICMA{months: months, regular: regular}, nil
+}
+
+// String returns "ACT/ACT ICMA".
+func (c ActActICMA) String() string {
+	return "ACT/ACT ICMA"
+}
+
+func (c ActActICMA) dayCount(start, end civil.Date) int {
+	return days(start, end)
+}
+
+func (c ActActICMA) yearFraction(start, end civil.Date) float64 {
+	months := c.months
+	if months == 0 {
+		months = 12
+	}
+	perYear := 12 / float64(months)
+	anchor := c.regular
+	if anchor.IsZero() {
+		anchor = end
+	}
+	eom := isLastDayOfMonth(anchor)
+	notional := func(k int) civil.Date {
+		return addMonths(anchor, k*months, eom)
+	}
+
+	// find the notional coupon period that contains start
+	k := floorDiv((start.Year()-anchor.Year())*12+int(start.Month())-int(anchor.Month()), months)
+	for notional(k).After(start) {
+		k--
+	}
+	for !notional(k + 1).After(start) {
+		k++
+	}
+
+	var fraction float64
+	for from := start; from.Before(end); k++ {
+		periodStart, periodEnd := notional(k), notional(k+1)
+		to := periodEnd
+		if end.Before(to) {
+			to = end
+		}
+		fraction += float64(days(from, to)) / (float64(days(periodStart, periodEnd)) * perYear)
+		from = to
+	}
+	return fraction
+}
+
+// Thirty360EISDA is 30E/360 ISDA, where the last day of a month is treated
+// as day 30, except for an end date that is the maturity date in February.
+type Thirty360EISDA struct {
+	// Maturity is the termination date of the instrument.
+	Maturity civil.Date
+}
+
+// String returns "30E/360 ISDA".
+func (c Thirty360EISDA) String() string {
+	return "30E/360 ISDA"
+}
+
+func (c Thirty360EISDA) dayCount(start, end civil.Date) int {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if isLastDayOfMonth(start) {
+		d1 = 30
+	}
+	if isLastDayOfMonth(end) && !(end.Equal(c.Maturity) && m2 == time.February) {
+		d2 = 30
+	}
+	return days360(y1, m1, d1, y2, m2, d2)
+}
+
+func (c Thirty360EISDA) yearFraction(start, end civil.Date) float64 {
+	return float64(c.dayCount(start, end)) / 360
+}
+
+type thirty360 struct {
+	name     string
+	february bool // adjust the last day of February, for 30/360 US
+	european bool // always adjust day 31 of the end date, for 30E/360
+}
+
+func (c thirty360) String() string {
+	return c.name
+}
+
+func (c thirty360) dayCount(start, end civil.Date) int {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if c.february {
+		lastFeb1 := m1 == time.February && isLastDayOfMonth(start)
+		if lastFeb1 && m2 == time.February && isLastDayOfMonth(end) {
+			d2 = 30
+		}
+		if lastFeb1 {
+			d1 = 30
+		}
+	}
+	if d2 == 31 && (d1 >= 30 || c.european) {
+		d2 = 30
+	}
+	if d1 == 31 {
+		d1 = 30
+	}
+	return days360(y1, m1, d1, y2, m2, d2)
+}
+
+func (c thirty360) yearFraction(start, end civil.Date) float64 {
+	return float64(c.dayCount(start, end)) / 360
+}
+
+func days360(y1 int, m1 time.Month, d1 int, y2 int, m2 time.Month, d2 int) int {
+	return 360*(y2-y1) + 30*int(m2-m1) + d2 - d1
+}
+
+// Business252 is BUS/252, which is the number of business days divided
+// by 252, as used in Brazil. Business days are counted from start up to,
+// but not including, end.
+//
+// The zero value counts Monday to Friday as business days, with no holidays.
+type Business252 struct {
+	calendar *civil.BusinessCalendar
+}
+
+var errNoCalendar = errors.New("daycount: BUS/252 requires a business calendar")
+
+// weekdays is the calendar used by the zero value of Business252.
+var weekdays = civil.NewBusinessCalendar(civil.Weekend)
+
+// NewBusiness252 returns the BUS/252 convention with business days
+// determined by cal, which must not be nil.
+func NewBusiness252(cal *civil.BusinessCalendar) (Business252, error) {
+	if cal == nil {
+		return Business252{}, errNoCalendar
+	}
+	return Business252{calendar: cal}, nil
+}
+
+// String returns "BUS/252".
+func (c Business252) String() string {
+	return "BUS/252"
+}
+
+func (c Business252) dayCount(start, end civil.Date) int {
+	cal := c.calendar
+	if cal == nil {
+		cal = weekdays
+	}
+	return cal.BusinessDaysBetween(start, end)
+}
+
+func (c Business252) yearFraction(start, end civil.Date) float64 {
+	return float64(c.dayCount(start, end)) / 252
+}
+
+func isLastDayOfMonth(d civil.Date) bool {
+	return d.AddDate(0, 0, 1).Day() == 1
+}
+
+// addMonths returns the date n months after d, which is the last day of the
+// month if eom is true or if the month has fewer days than d.
+func addMonths(d civil.Date, n int, eom bool) civil.Date {
+	year, month, day := d.Date()
+	year, month, _ = civil.DateFor(year, month, 1).AddDate(0, n, 0).Date()
+	last := civil.DateFor(year, month+1, 0).Day()
+	if eom || day > last {
+		day = last
+	}
+	return civil.DateFor(year, month, day)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,195 @@
+package daycount
+
+import (
+	"testing"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseDate(s string) civil.Date {
+	d, err := civil.ParseDate(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+// TestActualActual uses the examples in the ISDA memo "EMU and Market
+// Conventions: Recent Developments", which compares ACT/ACT ISDA with
+// ACT/ACT ICMA.
+func TestActualActual(t *testing.T) {
+	d := mustParseDate
+	semiAnnual := civil.PeriodOf(0, 6, 0)
+	annual := civil.PeriodOf(1, 0, 0)
+	testCases := []struct {
+		Name  string
+		Start civil.Date
+		End   civil.Date
+		Freq  civil.Period
+		Reg   civil.Date
+		ISDA  float64
+		Want  float64
+	}{
+		{
+			Name:  "regular",
+			Start: d("2003-11-01"), End: d("2004-05-01"),
+			Freq: semiAnnual,
+			ISDA: 0.49772, Want: 0.5,
+		},
+		{
+			Name:  "short first",
+			Start: d("1999-02-01"), End: d("1999-07-01"),
+			Freq: annual,
+			ISDA: 0.41096, Want: 0.41096,
+		},
+		{
+			Name:  "regular after short first",
+			Start: d("1999-07-01"), End: d("2000-07-01"),
+			Freq: annual,
+			ISDA: 1.00138, Want: 1,
+		},
+		{
+			Name:  "long first",
+			Start: d("2002-08-15"), End: d("2003-07-15"),
+			Freq: semiAnnual,
+			ISDA: 0.91507, Want: 0.91576,
+		},
+		{
+			Name:  "regular after long first",
+			Start: d("2003-07-15"), End: d("2004-01-15"),
+			Freq: semiAnnual,
+			ISDA: 0.50400, Want: 0.5,
+		},
+		{
+			Name:  "long final",
+			Start: d("1999-11-30"), End: d("2000-04-30"),
+			Freq: civil.PeriodOf(0, 3, 0), Reg: d("1999-11-30"),
+			ISDA: 0.41554, Want: 0.41576,
+		},
+	}
+	for _, tc := range testCases {
+		icma, err := NewActActICMA(tc.Freq, tc.Reg)
+		require.NoError(t, err, tc.Name)
+		assert.InDelta(t, tc.ISDA, YearFraction(tc.Start, tc.End, ActActISDA), 0.000005, tc.Name)
+		assert.InDelta(t, tc.Want, YearFraction(tc.Start, tc.End, icma), 0.000005, tc.Name)
+		assert.InDelta(t, -tc.Want, YearFraction(tc.End, tc.Start, icma), 0.000005, tc.Name)
+	}
+}
+
+func TestThirty360(t *testing.T) {
+	d := mustParseDate
+	testCases := []struct {
+		Start    civil.Date
+		End      civil.Date
+		Bond     int
+		US       int
+		E        int
+		EISDA    int
+		Maturity civil.Date
+	}{
+		{Start: d("2007-01-15"), End: d("2007-01-30"), Bond: 15, US: 15, E: 15, EISDA: 15},
+		{Start: d("2007-01-15"), End: d("2007-02-15"), Bond: 30, US: 30, E: 30, EISDA: 30},
+		{Start: d("2007-01-15"), End: d("2007-07-15"), Bond: 180, US: 180, E: 180, EISDA: 180},
+		{Start: d("2007-09-30"), End: d("2008-03-31"), Bond: 180, US: 180, E: 180, EISDA: 180},
+		{Start: d("2007-09-30"), End: d("2007-10-31"), Bond: 30, US: 30, E: 30, EISDA: 30},
+		{Start: d("2007-09-30"), End: d("2008-09-30"), Bond: 360, US: 360, E: 360, EISDA: 360},
+		{Start: d("2007-01-15"), End: d("2007-01-31"), Bond: 16, US: 16, E: 15, EISDA: 15},
+		{Start: d("2007-01-31"), End: d("2007-02-28"), Bond: 28, US: 28, E: 28, EISDA: 30},
+		{Start: d("2007-02-28"), End: d("2007-03-31"), Bond: 33, US: 30, E: 32, EISDA: 30},
+		{Start: d("2006-08-31"), End: d("2007-02-28"), Bond: 178, US: 178, E: 178, EISDA: 180},
+		{Start: d("2007-02-28"), End: d("2007-08-31"), Bond: 183, US: 180, E: 182, EISDA: 180},
+		{Start: d("2007-02-14"), End: d("2007-02-28"), Bond: 14, US: 14, E: 14, EISDA: 16},
+		{Start: d("2007-02-26"), End: d("2008-02-29"), Bond: 363, US: 363, E: 363, EISDA: 364},
+		{Start: d("2008-02-29"), End: d("2009-02-28"), Bond: 359, US: 360, E: 359, EISDA: 360},
+		{Start: d("2008-02-29"), End: d("2008-03-30"), Bond: 31, US: 30, E: 31, EISDA: 30},
+		{Start: d("2008-02-29"), End: d("2008-03-31"), Bond: 32, US: 30, E: 31, EISDA: 30},
+		{Start: d("2007-02-28"), End: d("2007-03-05"), Bond: 7, US: 5, E: 7, EISDA: 5},
+		{Start: d("2007-10-31"), End: d("2007-11-28"), Bond: 28, US: 28, E: 28, EISDA: 28},
+		{Start: d("2007-08-31"), End: d("2008-02-29"), Bond: 179, US: 179, E: 179, EISDA: 180},
+		{Start: d("2008-02-29"), End: d("2008-08-31"), Bond: 182, US: 180, E: 181, EISDA: 180},
+		{Start: d("2008-02-29"), End: d("2009-02-28"), Bond: 359, US: 360, E: 359, EISDA: 358, Maturity: d("2009-02-28")},
+	}
+	for _, tc := range testCases {
+		name := tc.Start.String() + " to " + tc.End.String()
+		assert.Equal(t, tc.Bond, DayCount(tc.Start, tc.End, Thirty360Bond), "30/360 "+name)
+		assert.Equal(t, tc.US, DayCount(tc.Start, tc.End, Thirty360US), "30/360 US "+name)
+		assert.Equal(t, tc.E, DayCount(tc.Start, tc.End, Thirty360E), "30E/360 "+name)
+		eisda := Thirty360EISDA{Maturity: tc.Maturity}
+		assert.Equal(t, tc.EISDA, DayCount(tc.Start, tc.End, eisda), "30E/360 ISDA "+name)
+		assert.InDelta(t, float64(tc.EISDA)/360, YearFraction(tc.Start, tc.End, eisda), 1e-12, name)
+	}
+}
+
+func TestActual(t *testing.T) {
+	assert := assert.New(t)
+	d := mustParseDate
+	start, end := d("2020-01-15"), d("2020-07-15")
+	assert.Equal(182, DayCount(start, end, Act360))
+	assert.Equal(-182, DayCount(end, start, Act360))
+	assert.InDelta(182.0/360, YearFraction(start, end, Act360), 1e-12)
+	assert.InDelta(182.0/365, YearFraction(start, end, Act365Fixed), 1e-12)
+	assert.InDelta(181.0/365, YearFraction(start, end, NL365), 1e-12)
+	assert.Equal(0, DayCount(start, start, NL365))
+
+	assert.Equal(27, DayCount(d("2020-02-01"), d("2020-02-29"), NL365))
+	assert.Equal(28, DayCount(d("2020-02-01"), d("2020-03-01"), NL365))
+	assert.Equal(28, DayCount(d("2020-02-29"), d("2020-03-28"), NL365))
+	assert.Equal(365, DayCount(d("2019-12-31"), d("2020-12-31"), NL365))
+	assert.Equal(365*4, DayCount(d("2019-01-01"), d("2023-01-01"), NL365))
+	assert.Equal(28, DayCount(d("2021-02-01"), d("2021-03-01"), NL365))
+}
+
+func TestBusiness252(t *testing.T) {
+	assert := assert.New(t)
+	d := mustParseDate
+	cal := civil.NewBusinessCalendar(civil.Weekend, civil.HolidayDates(d("2021-01-08")))
+	c, err := NewBusiness252(cal)
+	require.NoError(t, err)
+	assert.Equal(9, DayCount(d("2021-01-04"), d("2021-01-18"), c))
+	assert.Equal(-9, DayCount(d("2021-01-18"), d("2021-01-04"), c))
+	assert.InDelta(9.0/252, YearFraction(d("2021-01-04"), d("2021-01-18"), c), 1e-12)
+
+	// the zero value has no holidays
+	assert.Equal(10, DayCount(d("2021-01-04"), d("2021-01-18"), Business252{}))
+
+	_, err = NewBusiness252(nil)
+	assert.Error(err)
+}
+
+func TestICMAFrequency(t *testing.T) {
+	d := mustParseDate
+	for _, freq := range []civil.Period{{}, civil.PeriodOf(0, 0, 7), civil.PeriodOf(0, 6, 1), civil.PeriodOf(0, -6, 0)} {
+		_, err := NewActActICMA(freq, civil.Date{})
+		assert.Error(t, err, freq.String())
+	}
+
+	// the zero value is annual
+	annual, err := NewActActICMA(civil.PeriodOf(1, 0, 0), civil.Date{})
+	require.NoError(t, err)
+	assert.Equal(t, YearFraction(d("1999-02-01"), d("1999-07-01"), annual),
+		YearFraction(d("1999-02-01"), d("1999-07-01"), ActActICMA{}))
+}
+
+func TestString(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Convention Convention
+		Text       string
+	}{
+		{Act360, "ACT/360"},
+		{Act365Fixed, "ACT/365F"},
+		{NL365, "NL/365"},
+		{ActActISDA, "ACT/ACT ISDA"},
+		{ActActICMA{}, "ACT/ACT ICMA"},
+		{Thirty360Bond, "30/360"},
+		{Thirty360US, "30/360 US"},
+		{Thirty360E, "30E/360"},
+		{Thirty360EISDA{}, "30E/360 ISDA"},
+		{Business252{}, "BUS/252"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(tc.Text, tc.Convention.String())
+	}
+}
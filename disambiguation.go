@@ -0,0 +1,123 @@
+package civil
+
+import (
+	"fmt"
+	"time"
+)
+
+// Disambiguation determines how DateTime.In chooses an instant for a
+// civil date-time that falls in a gap or an overlap in a time zone.
+// The options match those of the JavaScript Temporal API.
+type Disambiguation int
+
+const (
+	// DisambiguateCompatible chooses the earlier instant in an overlap
+	// and the later instant in a gap. This moves a date-time in a gap
+	// forward by the length of the gap, which is usually what is wanted
+	// for a scheduled event.
+	DisambiguateCompatible Disambiguation = iota
+
+	// DisambiguateEarlier chooses the earlier instant in both a gap
+	// and an overlap.
+	DisambiguateEarlier
+
+	// DisambiguateLater chooses the later instant in both a gap
+	// and an overlap.
+	DisambiguateLater
+
+	// DisambiguateReject returns a *WallTimeError for a date-time in
+	// a gap or an overlap.
+	DisambiguateReject
+)
+
+// WallTimeKind classifies a civil date-time in a time zone.
+type WallTimeKind int
+
+const (
+	// WallTimeNormal means the date-time occurs exactly once.
+	WallTimeNormal WallTimeKind = iota
+
+	// WallTimeGap means the date-time is skipped, because clocks are
+	// turned forward across it.
+	WallTimeGap
+
+	// WallTimeOverlap means the date-time is repeated, because clocks are
+	// turned back across it.
+	WallTimeOverlap
+)
+
+var wallTimeKindNames = []string{
+	WallTimeNormal:  "normal",
+	WallTimeGap:     "gap",
+	WallTimeOverlap: "overlap",
+}
+
+// String returns "normal", "gap" or "overlap".
+func (k WallTimeKind) String() string {
+	if k >= 0 && int(k) < len(wallTimeKindNames) {
+		return wallTimeKindNames[k]
+	}
+	return fmt.Sprintf("WallTimeKind(%d)", int(k))
+}
+
+// WallTimeError is returned by DateTime.In when the date-time falls in
+// a gap or an overlap and the disambiguation is DisambiguateReject.
+type WallTimeError struct {
+	DateTime DateTime
+	Location *time.Location
+	Kind     WallTimeKind
+}
+
+func (e *WallTimeError) Error() string {
+	if e.Kind == WallTimeGap {
+		return fmt.Sprintf("%s does not occur in %s", e.DateTime, e.Location)
+	}
+	return fmt.Sprintf("%s occurs twice in %s", e.DateTime, e.Location)
+}
+
+// kind classifies the civil date-time.
+func (w wallTime) kind() WallTimeKind {
+	switch len(w.valid) {
+	case 0:
+		return WallTimeGap
+	case 1:
+		return WallTimeNormal
+	}
+	return WallTimeOverlap
+}
+
+// WallTimeIn reports whether dt occurs once in loc, or whether it falls in
+// a gap or an overlap caused by a change in the zone's offset.
+func (dt DateTime) WallTimeIn(loc *time.Location) WallTimeKind {
+	return lookupWallTime(dt, loc).kind()
+}
+
+// In returns the instant at which the civil date-time dt occurs in loc.
+//
+// When clocks are turned forward, some date-times do not occur in loc,
+// and when clocks are turned back, some date-times occur twice. The
+// disambiguation decides which instant to return in these cases. With
+// DisambiguateReject, In returns a *WallTimeError instead.
+//
+// Unlike time.Date, the result for a date-time in a gap or an overlap
+// is well defined. Use WallTimeIn to find out whether dt falls in a
+// gap or an overlap.
+func (dt DateTime) In(loc *time.Location, disambiguation Disambiguation) (time.Time, error) {
+	w := lookupWallTime(dt, loc)
+	kind := w.kind()
+	if kind == WallTimeNormal {
+		return w.instant(w.valid[0], loc), nil
+	}
+	switch disambiguation {
+	case DisambiguateEarlier:
+		return w.earlier(loc), nil
+	case DisambiguateLater:
+		return w.later(loc), nil
+	case DisambiguateReject:
+		return time.Time{}, &WallTimeError{DateTime: dt, Location: loc, Kind: kind}
+	}
+	if kind == WallTimeGap {
+		return w.later(loc), nil
+	}
+	return w.earlier(loc), nil
+}
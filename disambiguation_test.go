@@ -0,0 +1,81 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateTimeIn(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	newYork := mustLoadLocation("America/New_York")
+	testCases := []struct {
+		DateTime       string
+		Location       *time.Location
+		Disambiguation Disambiguation
+		Kind           WallTimeKind
+		Expected       string
+	}{
+		{"2021-03-01T10:00", sydney, DisambiguateCompatible, WallTimeNormal, "2021-03-01T10:00:00+11:00"},
+		{"2021-03-01T10:00", sydney, DisambiguateReject, WallTimeNormal, "2021-03-01T10:00:00+11:00"},
+
+		// Sydney clocks go forward from 02:00 to 03:00 on 2021-10-03
+		{"2021-10-03T02:30", sydney, DisambiguateCompatible, WallTimeGap, "2021-10-03T03:30:00+11:00"},
+		{"2021-10-03T02:30", sydney, DisambiguateEarlier, WallTimeGap, "2021-10-03T01:30:00+10:00"},
+		{"2021-10-03T02:30", sydney, DisambiguateLater, WallTimeGap, "2021-10-03T03:30:00+11:00"},
+		{"2021-10-03T02:00", sydney, DisambiguateCompatible, WallTimeGap, "2021-10-03T03:00:00+11:00"},
+
+		// Sydney clocks go back from 03:00 to 02:00 on 2021-04-04
+		{"2021-04-04T02:30", sydney, DisambiguateCompatible, WallTimeOverlap, "2021-04-04T02:30:00+11:00"},
+		{"2021-04-04T02:30", sydney, DisambiguateEarlier, WallTimeOverlap, "2021-04-04T02:30:00+11:00"},
+		{"2021-04-04T02:30", sydney, DisambiguateLater, WallTimeOverlap, "2021-04-04T02:30:00+10:00"},
+		{"2021-04-04T02:00", sydney, DisambiguateLater, WallTimeOverlap, "2021-04-04T02:00:00+10:00"},
+		{"2021-04-04T03:00", sydney, DisambiguateReject, WallTimeNormal, "2021-04-04T03:00:00+10:00"},
+
+		// New York, northern hemisphere
+		{"2021-03-14T02:30", newYork, DisambiguateCompatible, WallTimeGap, "2021-03-14T03:30:00-04:00"},
+		{"2021-11-07T01:30", newYork, DisambiguateCompatible, WallTimeOverlap, "2021-11-07T01:30:00-04:00"},
+		{"2021-11-07T01:30", newYork, DisambiguateLater, WallTimeOverlap, "2021-11-07T01:30:00-05:00"},
+
+		{"2021-03-01T10:00", time.UTC, DisambiguateReject, WallTimeNormal, "2021-03-01T10:00:00Z"},
+	}
+
+	for _, tc := range testCases {
+		dt := mustParseDateTime(tc.DateTime)
+		assert.Equal(tc.Kind, dt.WallTimeIn(tc.Location), tc.DateTime)
+		tm, err := dt.In(tc.Location, tc.Disambiguation)
+		if assert.NoError(err, tc.DateTime) {
+			assert.Equal(tc.Expected, tm.Format(time.RFC3339), tc.DateTime)
+			assert.Equal(tc.Location, tm.Location())
+		}
+	}
+}
+
+func TestDateTimeInReject(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+
+	_, err := mustParseDateTime("2021-10-03T02:30").In(sydney, DisambiguateReject)
+	if assert.Error(err) {
+		assert.Equal("2021-10-03T02:30:00 does not occur in Australia/Sydney", err.Error())
+		wtErr, ok := err.(*WallTimeError)
+		assert.True(ok)
+		assert.Equal(WallTimeGap, wtErr.Kind)
+	}
+
+	_, err = mustParseDateTime("2021-04-04T02:30").In(sydney, DisambiguateReject)
+	if assert.Error(err) {
+		assert.Equal("2021-04-04T02:30:00 occurs twice in Australia/Sydney", err.Error())
+		assert.Equal(WallTimeOverlap, err.(*WallTimeError).Kind)
+	}
+}
+
+func TestWallTimeKindString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("normal", WallTimeNormal.String())
+	assert.Equal("gap", WallTimeGap.String())
+	assert.Equal("overlap", WallTimeOverlap.String())
+	assert.Equal("WallTimeKind(7)", WallTimeKind(7).String())
+}
@@ -0,0 +1,218 @@
+// Package dosing expands medication regimens into schedules of doses.
+//
+// Dose times are civil date-times: a dose at 08:00 is taken at 08:00 in
+// whatever time zone the patient happens to be in. Use Rebase to convert
+// doses to instants for a patient who travels between time zones.
+package dosing
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+var (
+	errNoStart     = errors.New("regimen has no start date")
+	errNoEnd       = errors.New("regimen has no end date or dose count")
+	errNoTimes     = errors.New("regimen has no interval or times of day")
+	errBadInterval = errors.New("regimen interval is not a positive whole number of seconds")
+	errBadTime     = errors.New("regimen time of day is not within a day")
+	errBadTaper    = errors.New("regimen taper has no days")
+)
+
+const day = 24 * time.Hour
+
+// Dose is a single dose in a schedule.
+type Dose struct {
+	Time   civil.DateTime `json:"time"`
+	Amount float64        `json:"amount"`
+	Unit   string         `json:"unit,omitempty"`
+}
+
+// Regimen describes how a medication is to be taken.
+//
+// Doses are taken either at fixed times of day, such as three times daily at
+// 08:00, 14:00 and 20:00, or at a fixed interval, such as every 8 hours.
+// A regimen ends on its end date, after its dose count has been reached,
+// or after its taper, whichever comes first.
+type Regimen struct {
+	// Start is the date of the first dose.
+	Start civil.Date
+
+	// End is the last date on which doses are taken. If zero, the regimen
+	// ends when Count doses have been taken, or at the end of the taper.
+	End civil.Date
+
+	// Count is the number of doses. If zero, the regimen ends on End,
+	// or at the end of the taper.
+	Count int
+
+	// Times contains the times of day of each dose, as durations since
+	// midnight. For a regimen with an Every interval, the first entry is the
+	// time of the first dose, which is midnight if Times is empty.
+	Times []time.Duration
+
+	// Every is the interval between doses, which must be a whole number
+	// of seconds. If zero, doses are taken at each of Times.
+	Every time.Duration
+
+	// Days contains the days of the week on which doses are taken.
+	// If empty, doses are taken on every day.
+	Days civil.Weekdays
+
+	// Skip contains dates on which no doses are taken.
+	Skip []civil.Date
+
+	// Amount and Unit describe the dose, such as 500 "mg".
+	// Amount is ignored if the regimen has a Taper.
+	Amount float64
+	Unit   string
+
+	// Taper, if not nil, gives a dose amount that changes over time.
+	Taper *Taper
+}
+
+// Taper describes a dose amount that changes from one amount to another
+// over a number of days, such as 40mg reducing to 10mg over two weeks.
+// The amount stays at To after the taper is complete.
+type Taper struct {
+	From float64
+	To   float64
+
+	// Days is the number of days over which the amount changes.
+	// The first day has amount From, and the last day has amount To.
+	Days int
+
+	// Steps is the number of different amounts. For example, a taper from
+	// 40 to 10 with 4 steps gives amounts of 40, 30, 20 and 10, each for
+	// a quarter of the days. If Steps is zero, the amount changes each day.
+	Steps int
+
+	// Increment, if not zero, causes each amount to be rounded to the
+	// nearest multiple, such as the strength of a tablet.
+	Increment float64
+}
+
+// Amount returns the amount for a dose taken on the n'th day of
+// the taper, where the first day is zero.
+func (t Taper) Amount(n int) float64 {
+	if n <= 0 {
+		return t.round(t.From)
+	}
+	if n >= t.Days-1 {
+		return t.round(t.To)
+	}
+	var fraction float64
+	if t.Steps > 1 {
+		step := n * t.Steps / t.Days
+		fraction = float64(step) / float64(t.Steps-1)
+	} else {
+		fraction = float64(n) / float64(t.Days-1)
+	}
+	return t.round(t.From + (t.To-t.From)*fraction)
+}
+
+func (t Taper) round(amount float64) float64 {
+	if t.Increment <= 0 {
+		return amount
+	}
+	return math.Floor(amount/t.Increment+0.5) * t.Increment
+}
+
+// Doses returns the doses in the regimen, in order.
+func (r Regimen) Doses() ([]Dose, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	end := r.End
+	if r.Taper != nil && r.Count == 0 {
+		taperEnd := r.Start.AddDate(0, 0, r.Taper.Days-1)
+		if end.IsZero() || taperEnd.Before(end) {
+			end = taperEnd
+		}
+	}
+	skip := make(map[int64]bool, len(r.Skip))
+	for _, d := range r.Skip {
+		skip[d.Unix()] = true
+	}
+
+	var doses []Dose
+	for d, n := r.Start, 0; end.IsZero() || !d.After(end); d, n = d.AddDate(0, 0, 1), n+1 {
+		if skip[d.Unix()] || !(r.Days.IsEmpty() || r.Days.Has(d.Weekday())) {
+			continue
+		}
+		amount := r.Amount
+		if r.Taper != nil {
+			amount = r.Taper.Amount(n)
+		}
+		for _, dt := range r.timesOn(d) {
+			doses = append(doses, Dose{Time: dt, Amount: amount, Unit: r.Unit})
+			if r.Count > 0 && len(doses) == r.Count {
+				return doses, nil
+			}
+		}
+	}
+	return doses, nil
+}
+
+func (r Regimen) validate() error {
+	if r.Start.IsZero() {
+		return errNoStart
+	}
+	if r.End.IsZero() && r.Count <= 0 && r.Taper == nil {
+		return errNoEnd
+	}
+	if r.Every != 0 && (r.Every < time.Second || r.Every%time.Second != 0) {
+		// date-times have a resolution of one second
+		return errBadInterval
+	}
+	if r.Every == 0 && len(r.Times) == 0 {
+		return errNoTimes
+	}
+	for _, t := range r.Times {
+		if t < 0 || t >= day {
+			return errBadTime
+		}
+	}
+	if r.Taper != nil && r.Taper.Days <= 0 {
+		return errBadTaper
+	}
+	return nil
+}
+
+// timesOn returns the times of the doses on date d, ignoring skipped days.
+func (r Regimen) timesOn(d civil.Date) []civil.DateTime {
+	midnight := midnightOf(d)
+	var times []civil.DateTime
+	if r.Every == 0 {
+		for _, t := range r.Times {
+			times = append(times, midnight.Add(t))
+		}
+		sort.Slice(times, func(i, j int) bool {
+			return times[i].Before(times[j])
+		})
+		return times
+	}
+
+	first := midnightOf(r.Start)
+	if len(r.Times) > 0 {
+		first = first.Add(r.Times[0])
+	}
+	// index of the first dose at or after midnight
+	var k int64
+	if since := midnight.Sub(first); since > 0 {
+		k = int64((since + r.Every - 1) / r.Every)
+	}
+	for dt := first.Add(time.Duration(k) * r.Every); dt.Sub(midnight) < day; dt = dt.Add(r.Every) {
+		times = append(times, dt)
+	}
+	return times
+}
+
+func midnightOf(d civil.Date) civil.DateTime {
+	year, month, day := d.Date()
+	return civil.DateTimeFor(year, month, day, 0, 0, 0)
+}
@@ -0,0 +1,191 @@
+package dosing
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseDate(s string) civil.Date {
+	d, err := civil.ParseDate(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+func doseTimes(doses []Dose) []string {
+	var times []string
+	for _, dose := range doses {
+		times = append(times, dose.Time.String())
+	}
+	return times
+}
+
+func doseAmounts(doses []Dose) []float64 {
+	var amounts []float64
+	for _, dose := range doses {
+		amounts = append(amounts, dose.Amount)
+	}
+	return amounts
+}
+
+func TestTimesOfDay(t *testing.T) {
+	assert := assert.New(t)
+	r := Regimen{
+		Start:  mustParseDate("2021-03-01"),
+		End:    mustParseDate("2021-03-02"),
+		Times:  []time.Duration{20 * time.Hour, 8 * time.Hour, 14 * time.Hour},
+		Amount: 500,
+		Unit:   "mg",
+	}
+	doses, err := r.Doses()
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T08:00:00",
+		"2021-03-01T14:00:00",
+		"2021-03-01T20:00:00",
+		"2021-03-02T08:00:00",
+		"2021-03-02T14:00:00",
+		"2021-03-02T20:00:00",
+	}, doseTimes(doses))
+	assert.Equal(Dose{Time: doses[0].Time, Amount: 500, Unit: "mg"}, doses[0])
+
+	r.End = civil.Date{}
+	r.Count = 4
+	doses, err = r.Doses()
+	assert.NoError(err)
+	assert.Len(doses, 4)
+	assert.Equal("2021-03-02T08:00:00", doses[3].Time.String())
+}
+
+func TestEvery(t *testing.T) {
+	assert := assert.New(t)
+	r := Regimen{
+		Start: mustParseDate("2021-03-01"),
+		Times: []time.Duration{6 * time.Hour},
+		Every: 8 * time.Hour,
+		Count: 5,
+	}
+	doses, err := r.Doses()
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T06:00:00",
+		"2021-03-01T14:00:00",
+		"2021-03-01T22:00:00",
+		"2021-03-02T06:00:00",
+		"2021-03-02T14:00:00",
+	}, doseTimes(doses))
+
+	// every 36 hours, skipping a day
+	r = Regimen{
+		Start: mustParseDate("2021-03-01"),
+		End:   mustParseDate("2021-03-06"),
+		Times: []time.Duration{8 * time.Hour},
+		Every: 36 * time.Hour,
+		Skip:  []civil.Date{mustParseDate("2021-03-04")},
+	}
+	doses, err = r.Doses()
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T08:00:00",
+		"2021-03-02T20:00:00",
+		"2021-03-05T20:00:00",
+	}, doseTimes(doses))
+}
+
+func TestDaysAndSkip(t *testing.T) {
+	assert := assert.New(t)
+	r := Regimen{
+		Start: mustParseDate("2021-03-01"), // Monday
+		Count: 5,
+		Times: []time.Duration{9 * time.Hour},
+		Days:  civil.WeekdaysOf(time.Monday, time.Wednesday, time.Friday),
+		Skip:  []civil.Date{mustParseDate("2021-03-03")},
+	}
+	doses, err := r.Doses()
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T09:00:00",
+		"2021-03-05T09:00:00",
+		"2021-03-08T09:00:00",
+		"2021-03-10T09:00:00",
+		"2021-03-12T09:00:00",
+	}, doseTimes(doses))
+}
+
+func TestTaper(t *testing.T) {
+	assert := assert.New(t)
+	r := Regimen{
+		Start: mustParseDate("2021-03-01"),
+		Times: []time.Duration{8 * time.Hour},
+		Unit:  "mg",
+		Taper: &Taper{From: 40, To: 10, Days: 14, Steps: 4},
+	}
+	doses, err := r.Doses()
+	assert.NoError(err)
+	assert.Len(doses, 14)
+	assert.Equal([]float64{
+		40, 40, 40, 40,
+		30, 30, 30,
+		20, 20, 20, 20,
+		10, 10, 10,
+	}, doseAmounts(doses))
+	assert.Equal("2021-03-14T08:00:00", doses[13].Time.String())
+
+	// daily reduction rounded to 5mg tablets
+	taper := Taper{From: 40, To: 10, Days: 7, Increment: 5}
+	var amounts []float64
+	for n := 0; n < 8; n++ {
+		amounts = append(amounts, taper.Amount(n))
+	}
+	assert.Equal([]float64{40, 35, 30, 25, 20, 15, 10, 10}, amounts)
+
+	// end date earlier than the taper
+	r.End = mustParseDate("2021-03-03")
+	doses, err = r.Doses()
+	assert.NoError(err)
+	assert.Len(doses, 3)
+}
+
+func TestRegimenErrors(t *testing.T) {
+	start := mustParseDate("2021-03-01")
+	times := []time.Duration{8 * time.Hour}
+	testCases := []Regimen{
+		{Count: 1, Times: times},
+		{Start: start, Times: times},
+		{Start: start, Count: 1},
+		{Start: start, Count: 1, Every: -time.Hour},
+		{Start: start, Count: 1, Every: 500 * time.Millisecond},
+		{Start: start, Count: 1, Every: 90*time.Second + time.Millisecond},
+		{Start: start, Count: 1, Times: []time.Duration{25 * time.Hour}},
+		{Start: start, Times: times, Taper: &Taper{From: 10, To: 5}},
+	}
+	for i, r := range testCases {
+		_, err := r.Doses()
+		assert.Error(t, err, "test case %d", i)
+	}
+}
+
+func TestDoseJSON(t *testing.T) {
+	assert := assert.New(t)
+	r := Regimen{
+		Start:  mustParseDate("2021-03-01"),
+		Count:  2,
+		Times:  []time.Duration{8 * time.Hour, 20 * time.Hour},
+		Amount: 2.5,
+		Unit:   "ml",
+	}
+	doses, err := r.Doses()
+	assert.NoError(err)
+	data, err := json.Marshal(doses)
+	assert.NoError(err)
+	assert.Equal(`[{"time":"2021-03-01T08:00:00","amount":2.5,"unit":"ml"},{"time":"2021-03-01T20:00:00","amount":2.5,"unit":"ml"}]`, string(data))
+
+	var decoded []Dose
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(doses, decoded)
+}
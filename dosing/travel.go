@@ -0,0 +1,151 @@
+package dosing
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+var errNoItinerary = errors.New("itinerary has no locations")
+
+// ZoneChange records that a patient is in Location from instant At.
+type ZoneChange struct {
+	At       time.Time
+	Location *time.Location
+}
+
+// Itinerary is a patient's travel, as a list of zone changes. The first zone
+// change gives the patient's location at the start, and its At field is
+// ignored.
+type Itinerary []ZoneChange
+
+// Policy determines how the doses in a schedule are converted to instants
+// for a patient who travels.
+type Policy int
+
+const (
+	// KeepLocalTime takes each dose at its civil time in the location the
+	// patient is in at the time, so that a dose at 08:00 is taken at 08:00
+	// wherever the patient is. The interval between doses changes when the
+	// patient changes time zone. A dose whose time is skipped because of travel
+	// is taken on arrival.
+	KeepLocalTime Policy = iota
+
+	// KeepInterval takes each dose at its civil time in the patient's starting
+	// location, so that the intervals between doses are unchanged.
+	KeepInterval
+)
+
+// Flag indicates a problem with the interval before a dose.
+type Flag int
+
+const (
+	// FlagNone indicates that the interval is as planned, within tolerance.
+	FlagNone Flag = iota
+
+	// FlagTooClose indicates that the dose is too soon after the previous dose.
+	FlagTooClose
+
+	// FlagTooFar indicates that the dose is too long after the previous dose.
+	FlagTooFar
+)
+
+// String returns "none", "too-close" or "too-far".
+func (f Flag) String() string {
+	switch f {
+	case FlagTooClose:
+		return "too-close"
+	case FlagTooFar:
+		return "too-far"
+	}
+	return "none"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (f Flag) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// Resolved is a dose converted to an instant.
+type Resolved struct {
+	Dose
+
+	// At is the instant the dose is taken, in the patient's location.
+	At time.Time `json:"at"`
+
+	// Planned is the civil time between the previous dose and this dose,
+	// and Actual is the elapsed time between them. Both are zero for the
+	// first dose.
+	Planned time.Duration `json:"planned"`
+	Actual  time.Duration `json:"actual"`
+
+	// Flag is set if Actual differs from Planned by more than the tolerance.
+	Flag Flag `json:"flag"`
+}
+
+// Rebase converts doses, which must be in order, to instants for a patient
+// following itinerary. A dose is flagged if the elapsed time since the
+// previous dose differs from the planned interval by more than tolerance.
+func Rebase(doses []Dose, itinerary Itinerary, policy Policy, tolerance time.Duration) ([]Resolved, error) {
+	if len(itinerary) == 0 {
+		return nil, errNoItinerary
+	}
+	for _, zc := range itinerary {
+		if zc.Location == nil {
+			return nil, errNoItinerary
+		}
+	}
+	changes := make(Itinerary, len(itinerary))
+	copy(changes, itinerary)
+	rest := changes[1:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return rest[i].At.Before(rest[j].At)
+	})
+
+	resolved := make([]Resolved, len(doses))
+	for i, dose := range doses {
+		r := Resolved{Dose: dose}
+		if policy == KeepInterval {
+			r.At = instant(dose.Time, changes[0].Location)
+		} else {
+			r.At = changes.keepLocalTime(dose.Time)
+		}
+		if i > 0 {
+			prev := resolved[i-1]
+			r.Planned = dose.Time.Sub(prev.Time)
+			r.Actual = r.At.Sub(prev.At)
+			switch {
+			case r.Actual < r.Planned-tolerance:
+				r.Flag = FlagTooClose
+			case r.Actual > r.Planned+tolerance:
+				r.Flag = FlagTooFar
+			}
+		}
+		resolved[i] = r
+	}
+	return resolved, nil
+}
+
+// keepLocalTime returns the instant that dt occurs in the location the
+// patient is in at the time.
+func (it Itinerary) keepLocalTime(dt civil.DateTime) time.Time {
+	at := instant(dt, it[0].Location)
+	for _, zc := range it[1:] {
+		if at.Before(zc.At) {
+			return at
+		}
+		at = instant(dt, zc.Location)
+		if at.Before(zc.At) {
+			// dt was skipped when the patient arrived
+			return zc.At.In(zc.Location)
+		}
+	}
+	return at
+}
+
+func instant(dt civil.DateTime, loc *time.Location) time.Time {
+	t, _ := dt.In(loc, civil.DisambiguateCompatible)
+	return t
+}
@@ -0,0 +1,122 @@
+package dosing
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return loc
+}
+
+func twiceDaily(start string, count int) []Dose {
+	r := Regimen{
+		Start:  mustParseDate(start),
+		Count:  count,
+		Times:  []time.Duration{8 * time.Hour, 20 * time.Hour},
+		Amount: 1,
+	}
+	doses, err := r.Doses()
+	if err != nil {
+		panic(err.Error())
+	}
+	return doses
+}
+
+func resolvedTimes(resolved []Resolved) []string {
+	var times []string
+	for _, r := range resolved {
+		times = append(times, r.At.Format(time.RFC3339))
+	}
+	return times
+}
+
+func resolvedFlags(resolved []Resolved) []string {
+	var flags []string
+	for _, r := range resolved {
+		flags = append(flags, r.Flag.String())
+	}
+	return flags
+}
+
+func TestRebaseWest(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	london := mustLoadLocation("Europe/London")
+	itinerary := Itinerary{
+		{Location: sydney},
+		{At: time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC), Location: london},
+	}
+	doses := twiceDaily("2021-03-01", 6)
+
+	resolved, err := Rebase(doses, itinerary, KeepLocalTime, 2*time.Hour)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T08:00:00+11:00",
+		"2021-03-01T20:00:00+11:00",
+		"2021-03-02T08:00:00+11:00",
+		"2021-03-02T20:00:00+11:00",
+		"2021-03-03T08:00:00Z",
+		"2021-03-03T20:00:00Z",
+	}, resolvedTimes(resolved))
+	assert.Equal([]string{"none", "none", "none", "none", "too-far", "none"}, resolvedFlags(resolved))
+	assert.Equal(12*time.Hour, resolved[4].Planned)
+	assert.Equal(23*time.Hour, resolved[4].Actual)
+
+	resolved, err = Rebase(doses, itinerary, KeepInterval, 2*time.Hour)
+	assert.NoError(err)
+	assert.Equal("2021-03-03T08:00:00+11:00", resolved[4].At.Format(time.RFC3339))
+	assert.Equal([]string{"none", "none", "none", "none", "none", "none"}, resolvedFlags(resolved))
+}
+
+func TestRebaseEast(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	london := mustLoadLocation("Europe/London")
+	itinerary := Itinerary{
+		{Location: london},
+		{At: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), Location: sydney},
+	}
+	doses := twiceDaily("2021-03-01", 4)
+
+	// the dose at 08:00 on 2 March is skipped in both locations,
+	// so it is taken on arrival
+	resolved, err := Rebase(doses, itinerary, KeepLocalTime, 2*time.Hour)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"2021-03-01T08:00:00Z",
+		"2021-03-01T20:00:00Z",
+		"2021-03-02T11:00:00+11:00",
+		"2021-03-02T20:00:00+11:00",
+	}, resolvedTimes(resolved))
+	assert.Equal([]string{"none", "none", "too-close", "too-close"}, resolvedFlags(resolved))
+}
+
+func TestRebaseErrors(t *testing.T) {
+	doses := twiceDaily("2021-03-01", 2)
+	_, err := Rebase(doses, nil, KeepLocalTime, 0)
+	assert.Error(t, err)
+	_, err = Rebase(doses, Itinerary{{Location: time.UTC}, {At: time.Now()}}, KeepLocalTime, 0)
+	assert.Error(t, err)
+}
+
+func TestResolvedJSON(t *testing.T) {
+	assert := assert.New(t)
+	itinerary := Itinerary{{Location: time.UTC}}
+	resolved, err := Rebase(twiceDaily("2021-03-01", 2), itinerary, KeepLocalTime, 0)
+	assert.NoError(err)
+	data, err := json.Marshal(resolved[1])
+	assert.NoError(err)
+	s := string(data)
+	assert.True(strings.Contains(s, `"time":"2021-03-01T20:00:00"`), s)
+	assert.True(strings.Contains(s, `"at":"2021-03-01T20:00:00Z"`), s)
+	assert.True(strings.Contains(s, `"flag":"none"`), s)
+}
@@ -0,0 +1,303 @@
+// Package holidays calculates public holidays from rules, so that holiday
+// lists do not need to be updated every year.
+//
+// A holiday is defined by a rule, such as "12-25" for Christmas Day or
+// "easter-2" for Good Friday, and an observance, which determines the day
+// off given for a holiday that falls on the weekend. A Calendar of
+// definitions implements civil.HolidaySource, and can be used to build a
+// civil.BusinessCalendar.
+package holidays
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jjeffery/civil"
+)
+
+// Observance determines the date on which a holiday that falls on
+// the weekend is observed.
+type Observance int
+
+const (
+	// Actual observes the holiday on its date, even on the weekend.
+	Actual Observance = iota
+
+	// NextWeekday observes a holiday that falls on the weekend on the
+	// following weekday, which is Monday for a Saturday and Sunday weekend.
+	NextWeekday
+
+	// NearestWeekday observes a holiday that falls on the weekend on the
+	// nearest weekday, preferring the earlier day if both are as near.
+	// For a Saturday and Sunday weekend, a Saturday holiday is observed on
+	// Friday and a Sunday holiday on Monday.
+	NearestWeekday
+
+	// Substitute observes a holiday that falls on the weekend on the next
+	// weekday that is not already a holiday. When Christmas Day is on a
+	// Saturday and Boxing Day on a Sunday, they are observed on Monday
+	// and Tuesday.
+	Substitute
+)
+
+var observanceNames = []string{
+	Actual:         "actual",
+	NextWeekday:    "next-weekday",
+	NearestWeekday: "nearest-weekday",
+	Substitute:     "substitute",
+}
+
+// String returns the name of the observance, such as "next-weekday".
+func (o Observance) String() string {
+	if o >= 0 && int(o) < len(observanceNames) {
+		return observanceNames[o]
+	}
+	return fmt.Sprintf("Observance(%d)", int(o))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (o Observance) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (o *Observance) UnmarshalText(data []byte) error {
+	s := string(data)
+	if s == "" {
+		*o = Actual
+		return nil
+	}
+	for i, name := range observanceNames {
+		if strings.EqualFold(s, name) {
+			*o = Observance(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("holidays: invalid observance %q", s)
+}
+
+// Definition defines a holiday.
+type Definition struct {
+	Name       string
+	Rule       Rule
+	Observance Observance
+}
+
+type jsonDefinition struct {
+	Name       string     `json:"name"`
+	Rule       string     `json:"rule"`
+	Observance Observance `json:"observance,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The rule is
+// marshaled as a string in the format accepted by ParseRule.
+func (def Definition) MarshalJSON() ([]byte, error) {
+	jd := jsonDefinition{Name: def.Name, Observance: def.Observance}
+	if def.Rule != nil {
+		jd.Rule = def.Rule.String()
+	}
+	return json.Marshal(jd)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (def *Definition) UnmarshalJSON(data []byte) error {
+	var jd jsonDefinition
+	if err := json.Unmarshal(data, &jd); err != nil {
+		return err
+	}
+	rule, err := ParseRule(jd.Rule)
+	if err != nil {
+		return err
+	}
+	*def = Definition{Name: jd.Name, Rule: rule, Observance: jd.Observance}
+	return nil
+}
+
+// Parse parses holiday definitions, one per line, in the format
+//
+//	rule [observance] = name
+//
+// where rule is in the format accepted by ParseRule and observance is
+// one of "actual", "next-weekday", "nearest-weekday" or "substitute".
+// Blank lines and lines starting with "#" are ignored. For example:
+//
+//	# Australia
+//	01-01 substitute     = New Year's Day
+//	01-26 next-weekday   = Australia Day
+//	easter-2             = Good Friday
+//	easter+1             = Easter Monday
+//	2nd mon jun          = King's Birthday
+//	12-25 substitute     = Christmas Day
+//	12-26 substitute     = Boxing Day
+func Parse(s string) ([]Definition, error) {
+	var defs []Definition
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("holidays: line %d: missing holiday name", i+1)
+		}
+		def := Definition{Name: strings.TrimSpace(line[eq+1:])}
+		fields := strings.Fields(line[:eq])
+		if n := len(fields); n > 0 {
+			if err := def.Observance.UnmarshalText([]byte(fields[n-1])); err == nil {
+				fields = fields[:n-1]
+			}
+		}
+		rule, err := ParseRule(strings.Join(fields, " "))
+		if err != nil {
+			return nil, fmt.Errorf("holidays: line %d: %v", i+1, strings.TrimPrefix(err.Error(), "holidays: "))
+		}
+		def.Rule = rule
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// Holiday is a holiday in a particular year.
+type Holiday struct {
+	Name string `json:"name"`
+
+	// Date is the date on which the holiday is observed.
+	Date civil.Date `json:"date"`
+
+	// Actual is the date of the holiday, which is different to Date
+	// if the holiday falls on the weekend and is observed on another day.
+	Actual civil.Date `json:"actual"`
+}
+
+// Calendar calculates the holidays for a set of definitions. Holidays are
+// calculated when first needed and cached for each year.
+//
+// A Calendar is safe for concurrent use by multiple goroutines.
+type Calendar struct {
+	defs    []Definition
+	weekend civil.Weekdays
+
+	mu    sync.Mutex
+	years map[int][]Holiday
+}
+
+// New returns a calendar for the definitions. The weekend is used to
+// decide when a holiday is observed on another day.
+func New(weekend civil.Weekdays, defs ...Definition) *Calendar {
+	return &Calendar{
+		defs:    append([]Definition(nil), defs...),
+		weekend: weekend,
+		years:   make(map[int][]Holiday),
+	}
+}
+
+// HolidaysIn returns the holidays of the year, in order of the date they
+// are observed. A holiday observed in the previous or following year,
+// such as New Year's Day observed on the Friday before, is included in
+// its own year.
+func (c *Calendar) HolidaysIn(year int) []Holiday {
+	return append([]Holiday(nil), c.holidaysIn(year)...)
+}
+
+// IsHoliday reports whether a holiday is observed on d. It implements the
+// civil.HolidaySource interface.
+func (c *Calendar) IsHoliday(d civil.Date) bool {
+	return len(c.Lookup(d)) > 0
+}
+
+// Lookup returns the holidays observed on d.
+func (c *Calendar) Lookup(d civil.Date) []Holiday {
+	var holidays []Holiday
+	for year := d.Year() - 1; year <= d.Year()+1; year++ {
+		for _, h := range c.holidaysIn(year) {
+			if h.Date.Equal(d) {
+				holidays = append(holidays, h)
+			}
+		}
+	}
+	return holidays
+}
+
+// holidaysIn returns the cached holidays of the year.
+// The result must not be modified.
+func (c *Calendar) holidaysIn(year int) []Holiday {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	holidays, ok := c.years[year]
+	if !ok {
+		holidays = c.calculate(year)
+		c.years[year] = holidays
+	}
+	return holidays
+}
+
+// calculate returns the holidays of the year.
+func (c *Calendar) calculate(year int) []Holiday {
+	type entry struct {
+		Holiday
+		observance Observance
+	}
+	var entries []entry
+	taken := civil.NewDateSet()
+	for _, def := range c.defs {
+		if d, ok := def.Rule.Date(year); ok {
+			entries = append(entries, entry{
+				Holiday:    Holiday{Name: def.Name, Date: d, Actual: d},
+				observance: def.Observance,
+			})
+			taken.Add(d)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Actual.Before(entries[j].Actual)
+	})
+
+	// a calendar with no weekdays has nowhere to move holidays to
+	if c.weekend&civil.AllWeekdays != civil.AllWeekdays {
+		for i := range entries {
+			e := &entries[i]
+			if !c.weekend.Has(e.Actual.Weekday()) {
+				continue
+			}
+			switch e.observance {
+			case NextWeekday:
+				e.Date = c.weekday(e.Actual, 1)
+			case NearestWeekday:
+				next, prev := c.weekday(e.Actual, 1), c.weekday(e.Actual, -1)
+				if next.Sub(e.Actual) < e.Actual.Sub(prev) {
+					e.Date = next
+				} else {
+					e.Date = prev
+				}
+			case Substitute:
+				d := c.weekday(e.Actual, 1)
+				for taken.Has(d) {
+					d = c.weekday(d.AddDate(0, 0, 1), 1)
+				}
+				e.Date = d
+				taken.Add(d)
+			}
+		}
+	}
+
+	holidays := make([]Holiday, len(entries))
+	for i, e := range entries {
+		holidays[i] = e.Holiday
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// weekday returns the first day on or after d, if step is 1, or
+// on or before d, if step is -1, that is not on the weekend.
+func (c *Calendar) weekday(d civil.Date, step int) civil.Date {
+	for c.weekend.Has(d.Weekday()) {
+		d = d.AddDate(0, 0, step)
+	}
+	return d
+}
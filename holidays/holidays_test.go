@@ -0,0 +1,176 @@
+package holidays
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEaster(t *testing.T) {
+	testCases := []struct {
+		Year     int
+		Western  string
+		Orthodox string
+	}{
+		{2000, "2000-04-23", "2000-04-30"},
+		{2019, "2019-04-21", "2019-04-28"},
+		{2021, "2021-04-04", "2021-05-02"},
+		{2023, "2023-04-09", "2023-04-16"},
+		{2024, "2024-03-31", "2024-05-05"},
+		{2038, "2038-04-25", "2038-04-25"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Western, westernEaster(tc.Year).String())
+		assert.Equal(t, tc.Orthodox, orthodoxEaster(tc.Year).String())
+	}
+}
+
+func TestRules(t *testing.T) {
+	testCases := []struct {
+		Rule     string
+		Year     int
+		Expected string
+	}{
+		{"12-25", 2021, "2021-12-25"},
+		{"02-29", 2024, "2024-02-29"},
+		{"02-29", 2021, ""},
+		{"1st mon sep", 2021, "2021-09-06"},
+		{"2nd mon jun", 2021, "2021-06-14"},
+		{"last mon may", 2021, "2021-05-31"},
+		{"2nd-last fri dec", 2021, "2021-12-24"},
+		{"5th mon feb", 2021, ""},
+		{"4th thursday november", 2021, "2021-11-25"},
+		{"easter-2", 2021, "2021-04-02"},
+		{"easter+1", 2021, "2021-04-05"},
+		{"easter", 2021, "2021-04-04"},
+		{"orthodox-easter-2", 2021, "2021-04-30"},
+		{"2022-09-22", 2022, "2022-09-22"},
+		{"2022-09-22", 2021, ""},
+	}
+	for _, tc := range testCases {
+		rule, err := ParseRule(tc.Rule)
+		if !assert.NoError(t, err, tc.Rule) {
+			continue
+		}
+		d, ok := rule.Date(tc.Year)
+		if tc.Expected == "" {
+			assert.False(t, ok, tc.Rule)
+		} else if assert.True(t, ok, tc.Rule) {
+			assert.Equal(t, tc.Expected, d.String(), tc.Rule)
+		}
+
+		// String is the reverse of ParseRule
+		again, err := ParseRule(rule.String())
+		assert.NoError(t, err, tc.Rule)
+		assert.Equal(t, rule, again, tc.Rule)
+	}
+
+	for _, s := range []string{"", "13-01", "02-30", "easter2", "easterx", "6th mon jan", "1st-last mon jan", "1st mon-fri jan", "1st mon foo", "mon jan"} {
+		_, err := ParseRule(s)
+		assert.Error(t, err, s)
+	}
+}
+
+const australia = `
+# Australia
+01-01 substitute     = New Year's Day
+01-26 next-weekday   = Australia Day
+easter-2             = Good Friday
+easter+1             = Easter Monday
+2nd mon jun          = King's Birthday
+2022-09-22           = National Day of Mourning
+12-25 substitute     = Christmas Day
+12-26 substitute     = Boxing Day
+`
+
+func TestCalendar(t *testing.T) {
+	defs, err := Parse(australia)
+	require.NoError(t, err)
+	require.Len(t, defs, 8)
+	assert.Equal(t, Definition{Name: "Australia Day", Rule: Fixed{Month: time.January, Day: 26}, Observance: NextWeekday}, defs[1])
+
+	cal := New(civil.Weekend, defs...)
+	holidays := cal.HolidaysIn(2021)
+	var actual []string
+	for _, h := range holidays {
+		actual = append(actual, h.Date.String()+" "+h.Name)
+	}
+	assert.Equal(t, []string{
+		"2021-01-01 New Year's Day",
+		"2021-01-26 Australia Day",
+		"2021-04-02 Good Friday",
+		"2021-04-05 Easter Monday",
+		"2021-06-14 King's Birthday",
+		"2021-12-27 Christmas Day",
+		"2021-12-28 Boxing Day",
+	}, actual)
+	assert.Equal(t, civil.DateFor(2021, 12, 25), holidays[5].Actual)
+
+	assert.True(t, cal.IsHoliday(civil.DateFor(2021, 12, 28)))
+	assert.False(t, cal.IsHoliday(civil.DateFor(2021, 12, 25)))
+	assert.True(t, cal.IsHoliday(civil.DateFor(2022, 9, 22)))
+	assert.False(t, cal.IsHoliday(civil.DateFor(2021, 9, 22)))
+	// New Year's Day 2022 is a Saturday
+	assert.Equal(t, []Holiday{{Name: "New Year's Day", Date: civil.DateFor(2022, 1, 3), Actual: civil.DateFor(2022, 1, 1)}}, cal.Lookup(civil.DateFor(2022, 1, 3)))
+
+	// results are copies of the cache
+	holidays[0].Name = "changed"
+	assert.Equal(t, "New Year's Day", cal.HolidaysIn(2021)[0].Name)
+
+	// used as a holiday source for a business calendar
+	bc := civil.NewBusinessCalendar(civil.Weekend, cal)
+	assert.Equal(t, civil.DateFor(2021, 12, 29), bc.NextBusinessDay(civil.DateFor(2021, 12, 24)))
+}
+
+func TestNearestWeekday(t *testing.T) {
+	defs, err := Parse("01-01 nearest-weekday = New Year's Day\n07-04 nearest-weekday = Independence Day")
+	require.NoError(t, err)
+	cal := New(civil.Weekend, defs...)
+
+	// Saturday is observed on Friday, Sunday on Monday
+	assert.True(t, cal.IsHoliday(civil.DateFor(2020, 7, 3)))
+	assert.True(t, cal.IsHoliday(civil.DateFor(2021, 7, 5)))
+
+	// New Year's Day 2022 is observed in 2021
+	assert.True(t, cal.IsHoliday(civil.DateFor(2021, 12, 31)))
+	assert.Equal(t, civil.DateFor(2021, 12, 31), cal.HolidaysIn(2022)[0].Date)
+	assert.Len(t, cal.HolidaysIn(2021), 2)
+
+	// Friday and Saturday weekend
+	cal = New(civil.WeekdaysOf(time.Friday, time.Saturday), defs...)
+	assert.Equal(t, civil.DateFor(2022, 1, 2), cal.HolidaysIn(2022)[0].Date)
+}
+
+func TestDefinitionJSON(t *testing.T) {
+	data := []byte(`[{"name":"Good Friday","rule":"easter-2"},{"name":"Christmas Day","rule":"12-25","observance":"substitute"}]`)
+	var defs []Definition
+	require.NoError(t, json.Unmarshal(data, &defs))
+	assert.Equal(t, []Definition{
+		{Name: "Good Friday", Rule: Easter{Offset: -2}},
+		{Name: "Christmas Day", Rule: Fixed{Month: time.December, Day: 25}, Observance: Substitute},
+	}, defs)
+
+	again, err := json.Marshal(defs)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+
+	assert.Error(t, json.Unmarshal([]byte(`[{"name":"x","rule":"bad"}]`), &defs))
+	assert.Error(t, json.Unmarshal([]byte(`[{"name":"x","rule":"12-25","observance":"bad"}]`), &defs))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"12-25 Christmas Day",
+		"12-32 = Christmas Day",
+		"substitute = Christmas Day",
+	} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+	assert.Equal(t, "next-weekday", NextWeekday.String())
+	assert.Equal(t, "Observance(9)", Observance(9).String())
+}
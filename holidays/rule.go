@@ -0,0 +1,243 @@
+package holidays
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Rule determines the date of a holiday in a year.
+type Rule interface {
+	// Date returns the date of the holiday in the year, and false if
+	// there is no such holiday in the year.
+	Date(year int) (civil.Date, bool)
+
+	// String returns the rule in the format accepted by ParseRule.
+	String() string
+}
+
+// Fixed is a holiday on the same day every year, such as Christmas Day.
+type Fixed struct {
+	Month time.Month
+	Day   int
+}
+
+// Date returns the date in the year. A rule for 29 February has no
+// date in years that are not leap years.
+func (r Fixed) Date(year int) (civil.Date, bool) {
+	d := civil.DateFor(year, r.Month, r.Day)
+	return d, d.Month() == r.Month && d.Day() == r.Day
+}
+
+// String returns the month and day, such as "12-25".
+func (r Fixed) String() string {
+	return fmt.Sprintf("%02d-%02d", int(r.Month), r.Day)
+}
+
+// NthWeekday is a holiday on the nth weekday of a month, such as the first
+// Monday in September. If N is negative, it counts back from the end of the
+// month, so -1 is the last weekday of the month.
+type NthWeekday struct {
+	N       int
+	Weekday time.Weekday
+	Month   time.Month
+}
+
+// Date returns the date in the year, and false if the month has fewer
+// than N of the weekday.
+func (r NthWeekday) Date(year int) (civil.Date, bool) {
+	var d civil.Date
+	if r.N > 0 {
+		first := civil.DateFor(year, r.Month, 1)
+		offset := (int(r.Weekday) - int(first.Weekday()) + 7) % 7
+		d = first.AddDate(0, 0, offset+(r.N-1)*7)
+	} else if r.N < 0 {
+		last := civil.DateFor(year, r.Month+1, 0)
+		offset := (int(last.Weekday()) - int(r.Weekday) + 7) % 7
+		d = last.AddDate(0, 0, -offset+(r.N+1)*7)
+	} else {
+		return d, false
+	}
+	return d, d.Month() == r.Month
+}
+
+var ordinals = []string{"", "1st", "2nd", "3rd", "4th", "5th"}
+
+// String returns the rule, such as "1st mon sep" or "last mon may".
+func (r NthWeekday) String() string {
+	var n string
+	switch {
+	case r.N == -1:
+		n = "last"
+	case r.N < 0 && -r.N < len(ordinals):
+		n = ordinals[-r.N] + "-last"
+	case r.N > 0 && r.N < len(ordinals):
+		n = ordinals[r.N]
+	default:
+		n = strconv.Itoa(r.N)
+	}
+	return n + " " + strings.ToLower(r.Weekday.String()[:3]) + " " + strings.ToLower(r.Month.String()[:3])
+}
+
+// Easter is a holiday that is a number of days before or after Easter
+// Sunday, such as Good Friday, which has an Offset of -2.
+type Easter struct {
+	Offset int
+
+	// Orthodox is true for Orthodox Easter, which is calculated using the
+	// Julian calendar. Otherwise Easter is calculated using the Gregorian
+	// calendar, as observed by Western churches.
+	Orthodox bool
+}
+
+// Date returns the date in the year.
+func (r Easter) Date(year int) (civil.Date, bool) {
+	var d civil.Date
+	if r.Orthodox {
+		d = orthodoxEaster(year)
+	} else {
+		d = westernEaster(year)
+	}
+	return d.AddDate(0, 0, r.Offset), true
+}
+
+// String returns the rule, such as "easter-2" or "orthodox-easter+1".
+func (r Easter) String() string {
+	s := "easter"
+	if r.Orthodox {
+		s = "orthodox-easter"
+	}
+	if r.Offset != 0 {
+		s += fmt.Sprintf("%+d", r.Offset)
+	}
+	return s
+}
+
+// westernEaster returns the date of Easter Sunday in the Gregorian calendar,
+// using the anonymous Gregorian algorithm.
+func westernEaster(year int) civil.Date {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return civil.DateFor(year, time.Month(month), day)
+}
+
+// orthodoxEaster returns the date of Orthodox Easter Sunday, calculated in
+// the Julian calendar using the Meeus algorithm, and converted to the
+// Gregorian calendar.
+func orthodoxEaster(year int) civil.Date {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+	// difference between the Julian and Gregorian calendars
+	diff := year/100 - year/400 - 2
+	return civil.DateFor(year, time.Month(month), day+diff)
+}
+
+// Once is a holiday that occurs only once, such as a national day of mourning.
+type Once civil.Date
+
+// Date returns the date, and false if year is not the year of the date.
+func (r Once) Date(year int) (civil.Date, bool) {
+	d := civil.Date(r)
+	return d, d.Year() == year
+}
+
+// String returns the date, such as "2022-09-22".
+func (r Once) String() string {
+	return civil.Date(r).String()
+}
+
+// ParseRule parses a rule, which is one of:
+//
+//	12-25              month and day (Fixed)
+//	1st mon sep        nth weekday of the month (NthWeekday); the first
+//	                   field is 1st to 5th, last, or 2nd-last to 5th-last
+//	easter-2           days after Easter (Easter), which may be negative
+//	orthodox-easter+1  days after Orthodox Easter (Easter)
+//	2022-09-22         a single date (Once)
+func ParseRule(s string) (Rule, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	switch len(fields) {
+	case 1:
+		f := fields[0]
+		for _, prefix := range []string{"orthodox-easter", "easter"} {
+			if strings.HasPrefix(f, prefix) {
+				r := Easter{Orthodox: prefix == "orthodox-easter"}
+				if rest := f[len(prefix):]; rest != "" {
+					n, err := strconv.Atoi(rest)
+					if err != nil || (rest[0] != '+' && rest[0] != '-') {
+						return nil, fmt.Errorf("holidays: invalid rule %q", s)
+					}
+					r.Offset = n
+				}
+				return r, nil
+			}
+		}
+		if d, err := civil.ParseDate(f); err == nil && len(f) == 10 {
+			return Once(d), nil
+		}
+		if len(f) == 5 && f[2] == '-' {
+			month, err1 := strconv.Atoi(f[:2])
+			day, err2 := strconv.Atoi(f[3:])
+			if err1 == nil && err2 == nil && month >= 1 && month <= 12 && day >= 1 && day <= daysIn(time.Month(month)) {
+				return Fixed{Month: time.Month(month), Day: day}, nil
+			}
+		}
+	case 3:
+		n, ok := parseOrdinal(fields[0])
+		weekdays, err := civil.ParseWeekdays(fields[1])
+		month, mok := parseMonth(fields[2])
+		if ok && err == nil && weekdays.Count() == 1 && mok {
+			return NthWeekday{N: n, Weekday: weekdays.Days(time.Sunday)[0], Month: month}, nil
+		}
+	}
+	return nil, fmt.Errorf("holidays: invalid rule %q", s)
+}
+
+// daysIn returns the most days month can have.
+func daysIn(month time.Month) int {
+	// 2000 is a leap year
+	return civil.DateFor(2000, month+1, 0).Day()
+}
+
+// parseOrdinal parses "1st" to "5th", "last", and "2nd-last" to "5th-last".
+func parseOrdinal(s string) (int, bool) {
+	if s == "last" {
+		return -1, true
+	}
+	sign := 1
+	if strings.HasSuffix(s, "-last") {
+		s, sign = strings.TrimSuffix(s, "-last"), -1
+	}
+	for n := 1; n < len(ordinals); n++ {
+		if s == ordinals[n] && (sign > 0 || n > 1) {
+			return sign * n, true
+		}
+	}
+	return 0, false
+}
+
+// parseMonth parses a month name or its three letter abbreviation.
+func parseMonth(s string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if s == name || s == name[:3] {
+			return month, true
+		}
+	}
+	return 0, false
+}
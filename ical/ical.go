@@ -0,0 +1,194 @@
+// Package ical reads and writes iCalendar (RFC 5545) events using civil
+// dates and date-times.
+//
+// An iCalendar DATE value, such as "20210301", is a civil.Date, and a floating
+// DATE-TIME value, such as "20210301T083000", is a civil.DateTime. All-day events,
+// holiday feeds and schedules that follow local time can be exchanged with
+// calendar clients without converting to and from time.Time.
+package ical
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/jjeffery/civil/rrule"
+)
+
+// Calendar is an iCalendar object (VCALENDAR).
+type Calendar struct {
+	// ProdID identifies the product that created the calendar.
+	ProdID string
+
+	Events []*Event
+
+	// Other contains the properties and components of the calendar that
+	// are not otherwise recognised, such as VTIMEZONE components, in the
+	// order they appear.
+	Other []Property
+}
+
+// Event is a VEVENT or VTODO component.
+type Event struct {
+	// Component is "VEVENT" or "VTODO". If empty, "VEVENT" is assumed.
+	Component string
+
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+
+	// Start, End and Due are the DTSTART, DTEND and DUE properties.
+	// Any of them may be zero.
+	Start Value
+	End   Value
+	Due   Value
+
+	// Other contains the properties of the event that are not otherwise
+	// recognised, such as DTSTAMP and RRULE, and nested components such
+	// as VALARM, in the order they appear.
+	Other []Property
+}
+
+// Property is a content line, such as "RRULE:FREQ=DAILY".
+type Property struct {
+	Name   string
+	Params []Param
+
+	// Value is the raw value of the property, which is not unescaped.
+	Value string
+}
+
+// Param is a property parameter, such as "TZID=Australia/Sydney".
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Param returns the value of the named parameter, or the empty string.
+func (p Property) Param(name string) string {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Value is the value of a DATE or DATE-TIME property such as DTSTART.
+type Value struct {
+	// DateTime is the date and time. If IsDate is true, the time is midnight.
+	DateTime civil.DateTime
+
+	// IsDate is true for a DATE value (VALUE=DATE).
+	IsDate bool
+
+	// TZID is the time zone of the value, as specified by the TZID
+	// parameter. It is empty for floating values and UTC values.
+	TZID string
+
+	// UTC is true if the value is in UTC, which is indicated by a
+	// trailing "Z", such as "20210301T083000Z".
+	UTC bool
+}
+
+// DateValue returns a DATE value.
+func DateValue(d civil.Date) Value {
+	year, month, day := d.Date()
+	return Value{DateTime: civil.DateTimeFor(year, month, day, 0, 0, 0), IsDate: true}
+}
+
+// FloatingValue returns a floating DATE-TIME value, which is a civil date-time
+// without a time zone.
+func FloatingValue(dt civil.DateTime) Value {
+	return Value{DateTime: dt}
+}
+
+// IsZero reports whether the value is not set.
+func (v Value) IsZero() bool {
+	return v.DateTime.IsZero()
+}
+
+// IsFloating reports whether the value is a DATE-TIME without a time zone.
+func (v Value) IsFloating() bool {
+	return !v.IsDate && v.TZID == "" && !v.UTC
+}
+
+// Date returns the date of the value.
+func (v Value) Date() civil.Date {
+	year, month, day := v.DateTime.Date()
+	return civil.DateFor(year, month, day)
+}
+
+// Instant returns the instant of the value. Values with a TZID use that
+// time zone, UTC values use UTC, and floating values and dates use floating.
+// Date-times that are skipped or repeated in the time zone are resolved with
+// civil.DisambiguateCompatible.
+func (v Value) Instant(floating *time.Location) (time.Time, error) {
+	loc := floating
+	switch {
+	case v.UTC:
+		loc = time.UTC
+	case v.TZID != "":
+		var err error
+		if loc, err = civil.LoadLocation(v.TZID); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if loc == nil {
+		return time.Time{}, errors.New("ical: no location for floating value")
+	}
+	return v.DateTime.In(loc, civil.DisambiguateCompatible)
+}
+
+// property returns the value as a property with the given name.
+func (v Value) property(name string) Property {
+	p := Property{Name: name, Value: rrule.FormatValue(v.DateTime, v.IsDate)}
+	if v.IsDate {
+		p.Params = append(p.Params, Param{Name: "VALUE", Value: "DATE"})
+	} else if v.UTC {
+		p.Value += "Z"
+	} else if v.TZID != "" {
+		p.Params = append(p.Params, Param{Name: "TZID", Value: v.TZID})
+	}
+	return p
+}
+
+// parseValue parses the value of a DATE or DATE-TIME property.
+func parseValue(p Property) (Value, error) {
+	v := Value{TZID: p.Param("TZID")}
+	dt, isDate, isUTC, err := rrule.ParseValue(p.Value)
+	if err != nil {
+		return v, err
+	}
+	if strings.EqualFold(p.Param("VALUE"), "DATE") != isDate {
+		return v, errors.New("value does not match the VALUE parameter")
+	}
+	v.DateTime, v.IsDate, v.UTC = dt, isDate, isUTC
+	if isDate || isUTC {
+		v.TZID = ""
+	}
+	return v, nil
+}
+
+// RecurrenceSet returns the recurrence set of the event, which is built from
+// its DTSTART, RRULE, RDATE and EXDATE properties. It returns nil if the event
+// has no RRULE or RDATE.
+func (e *Event) RecurrenceSet() (*rrule.Set, error) {
+	lines := []string{e.Start.property("DTSTART").String()}
+	recurs := false
+	for _, p := range e.Other {
+		switch strings.ToUpper(p.Name) {
+		case "RRULE", "RDATE":
+			recurs = true
+			fallthrough
+		case "EXDATE":
+			lines = append(lines, p.String())
+		}
+	}
+	if !recurs {
+		return nil, nil
+	}
+	return rrule.Parse(strings.Join(lines, "\n"))
+}
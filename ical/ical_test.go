@@ -0,0 +1,221 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//Holidays//EN
+BEGIN:VTIMEZONE
+TZID:Australia/Sydney
+BEGIN:STANDARD
+DTSTART:19700405T030000
+TZOFFSETFROM:+1100
+TZOFFSETTO:+1000
+END:STANDARD
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:holiday-1@example.com
+DTSTART;VALUE=DATE:20210301
+DTEND;VALUE=DATE:20210302
+SUMMARY:Labour Day\, Victoria
+DESCRIPTION:A public holiday.\nOffices are closed.
+END:VEVENT
+BEGIN:VEVENT
+UID:standup@example.com
+DTSTART:20210301T083000
+DTEND:20210301T084500
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3
+SUMMARY:Stand-up meeting that has a summary long enough to need folding onto
+  another line
+BEGIN:VALARM
+ACTION:DISPLAY
+TRIGGER:-PT5M
+END:VALARM
+END:VEVENT
+BEGIN:VTODO
+UID:todo@example.com
+DTSTART;TZID=Australia/Sydney:20210301T090000
+DUE:20210301T230000Z
+LOCATION:Level 3
+END:VTODO
+END:VCALENDAR
+`
+
+func TestRead(t *testing.T) {
+	cal, err := Read(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+	assert.Equal(t, "-//Example//Holidays//EN", cal.ProdID)
+	assert.Len(t, cal.Other, 8)
+	assert.Equal(t, "BEGIN", cal.Other[0].Name)
+	assert.Equal(t, "VTIMEZONE", cal.Other[0].Value)
+	require.Len(t, cal.Events, 3)
+
+	holiday := cal.Events[0]
+	assert.Equal(t, "VEVENT", holiday.Component)
+	assert.Equal(t, "Labour Day, Victoria", holiday.Summary)
+	assert.Equal(t, "A public holiday.\nOffices are closed.", holiday.Description)
+	assert.Equal(t, DateValue(civil.DateFor(2021, 3, 1)), holiday.Start)
+	assert.Equal(t, DateValue(civil.DateFor(2021, 3, 2)), holiday.End)
+	assert.Equal(t, civil.DateFor(2021, 3, 2), holiday.End.Date())
+
+	standup := cal.Events[1]
+	assert.Equal(t, "Stand-up meeting that has a summary long enough to need folding onto another line", standup.Summary)
+	assert.Equal(t, FloatingValue(civil.DateTimeFor(2021, 3, 1, 8, 30, 0)), standup.Start)
+	assert.True(t, standup.Start.IsFloating())
+	assert.True(t, standup.Due.IsZero())
+	assert.Len(t, standup.Other, 5)
+
+	todo := cal.Events[2]
+	assert.Equal(t, "VTODO", todo.Component)
+	assert.Equal(t, "Level 3", todo.Location)
+	assert.Equal(t, Value{DateTime: civil.DateTimeFor(2021, 3, 1, 9, 0, 0), TZID: "Australia/Sydney"}, todo.Start)
+	assert.Equal(t, Value{DateTime: civil.DateTimeFor(2021, 3, 1, 23, 0, 0), UTC: true}, todo.Due)
+	assert.False(t, todo.Start.IsFloating())
+}
+
+func TestRoundTrip(t *testing.T) {
+	cal, err := Read(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Write(&buf))
+	text := buf.String()
+	assert.Contains(t, text, "DTSTART;VALUE=DATE:20210301\r\n")
+	assert.Contains(t, text, "DTSTART:20210301T083000\r\n")
+	assert.Contains(t, text, "DTSTART;TZID=Australia/Sydney:20210301T090000\r\n")
+	assert.Contains(t, text, "DUE:20210301T230000Z\r\n")
+	assert.Contains(t, text, "SUMMARY:Labour Day\\, Victoria\r\n")
+	for _, line := range strings.Split(text, "\r\n") {
+		assert.True(t, len(line) <= 75, line)
+	}
+
+	again, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, cal, again)
+}
+
+func TestWrite(t *testing.T) {
+	cal := &Calendar{
+		Events: []*Event{
+			{
+				UID:     "1",
+				Start:   DateValue(civil.DateFor(2021, 12, 25)),
+				Summary: strings.Repeat("Noël ", 20),
+				Other: []Property{
+					{Name: "ATTENDEE", Params: []Param{{Name: "CN", Value: "Smith, Jo"}}, Value: "mailto:jo@example.com"},
+				},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, cal.Write(&buf))
+	text := buf.String()
+	assert.True(t, strings.HasPrefix(text, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:"+DefaultProdID+"\r\nBEGIN:VEVENT\r\n"))
+	assert.Contains(t, text, `ATTENDEE;CN="Smith, Jo":mailto:jo@example.com`)
+	for _, line := range strings.Split(text, "\r\n") {
+		assert.True(t, len(line) <= 75, line)
+		assert.True(t, !strings.ContainsRune(line, '�'), line)
+	}
+
+	again, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, cal.Events[0].Summary, again.Events[0].Summary)
+	assert.Equal(t, "Smith, Jo", again.Events[0].Other[0].Param("cn"))
+}
+
+func TestRecurrenceSet(t *testing.T) {
+	cal, err := Read(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+
+	set, err := cal.Events[0].RecurrenceSet()
+	assert.NoError(t, err)
+	assert.Nil(t, set)
+
+	set, err = cal.Events[1].RecurrenceSet()
+	require.NoError(t, err)
+	var actual []string
+	for _, dt := range set.All() {
+		actual = append(actual, dt.String())
+	}
+	assert.Equal(t, []string{"2021-03-01T08:30:00", "2021-03-03T08:30:00", "2021-03-08T08:30:00"}, actual)
+}
+
+func TestRecurrenceSetUntilUTC(t *testing.T) {
+	text := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:daily@example.com",
+		"DTSTART;TZID=America/New_York:19971220T200000",
+		"RRULE:FREQ=DAILY;UNTIL=19971224T000000Z",
+		"EXDATE:19971222T010000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	cal, err := Read(strings.NewReader(text))
+	require.NoError(t, err)
+	require.Len(t, cal.Events, 1)
+
+	set, err := cal.Events[0].RecurrenceSet()
+	require.NoError(t, err)
+	var actual []string
+	for _, dt := range set.All() {
+		actual = append(actual, dt.String())
+	}
+	assert.Equal(t, []string{"1997-12-20T20:00:00", "1997-12-22T20:00:00"}, actual)
+}
+
+func TestInstant(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	testCases := []struct {
+		Value    Value
+		Floating *time.Location
+		Expected string
+	}{
+		{FloatingValue(civil.DateTimeFor(2021, 3, 1, 8, 30, 0)), time.UTC, "2021-03-01T08:30:00Z"},
+		{FloatingValue(civil.DateTimeFor(2021, 3, 1, 8, 30, 0)), sydney, "2021-03-01T08:30:00+11:00"},
+		{DateValue(civil.DateFor(2021, 3, 1)), sydney, "2021-03-01T00:00:00+11:00"},
+		{Value{DateTime: civil.DateTimeFor(2021, 3, 1, 9, 0, 0), TZID: "Australia/Sydney"}, nil, "2021-03-01T09:00:00+11:00"},
+		{Value{DateTime: civil.DateTimeFor(2021, 3, 1, 23, 0, 0), UTC: true}, sydney, "2021-03-01T23:00:00Z"},
+	}
+	for i, tc := range testCases {
+		at, err := tc.Value.Instant(tc.Floating)
+		if assert.NoError(t, err, "test case %d", i) {
+			assert.Equal(t, tc.Expected, at.Format(time.RFC3339), "test case %d", i)
+		}
+	}
+
+	_, err = FloatingValue(civil.DateTimeFor(2021, 3, 1, 8, 30, 0)).Instant(nil)
+	assert.Error(t, err)
+	_, err = Value{DateTime: civil.DateTimeFor(2021, 3, 1, 9, 0, 0), TZID: "No/Such_Zone"}.Instant(nil)
+	assert.Error(t, err)
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"BEGIN:VEVENT\nEND:VEVENT\n",
+		"BEGIN:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VTODO\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VTIMEZONE\nEND:VALARM\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nEND:VEVENT\n",
+		"BEGIN:VCALENDAR\nno colon\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nX-PROP;PARAM:value\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nX-PROP;PARAM=\"a:b\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2021-03-01\nEND:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20210301T083000\nEND:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20210301\nEND:VEVENT\nEND:VCALENDAR\n",
+	} {
+		_, err := Read(strings.NewReader(text))
+		assert.Error(t, err, text)
+	}
+}
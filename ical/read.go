@@ -0,0 +1,198 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineLength is the longest unfolded content line that is accepted.
+const maxLineLength = 1 << 20
+
+// Read reads a calendar from r. Folded lines are unfolded, and lines may end
+// with either CRLF or LF. Only the first VCALENDAR is read.
+func Read(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var cal *Calendar
+	var event *Event
+	var nested []string // names of components nested in the current one
+	for i, line := range lines {
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %v", i+1, err)
+		}
+		name := strings.ToUpper(p.Name)
+		value := strings.ToUpper(p.Value)
+
+		if cal == nil {
+			if name != "BEGIN" || value != "VCALENDAR" {
+				return nil, fmt.Errorf("ical: line %d: expected BEGIN:VCALENDAR", i+1)
+			}
+			cal = &Calendar{}
+			continue
+		}
+
+		// nested components are kept as they are
+		if name == "BEGIN" && (len(nested) > 0 || event != nil || (value != "VEVENT" && value != "VTODO")) {
+			nested = append(nested, value)
+		}
+		if len(nested) > 0 {
+			if event != nil {
+				event.Other = append(event.Other, p)
+			} else {
+				cal.Other = append(cal.Other, p)
+			}
+			if name == "END" {
+				if value != nested[len(nested)-1] {
+					return nil, fmt.Errorf("ical: line %d: unexpected END:%s", i+1, p.Value)
+				}
+				nested = nested[:len(nested)-1]
+			}
+			continue
+		}
+
+		switch {
+		case name == "BEGIN":
+			event = &Event{Component: value}
+		case name == "END" && event != nil:
+			if value != event.Component {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", i+1, p.Value)
+			}
+			cal.Events = append(cal.Events, event)
+			event = nil
+		case name == "END":
+			if value != "VCALENDAR" {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", i+1, p.Value)
+			}
+			return cal, nil
+		case event != nil:
+			if err := event.setProperty(name, p); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %s: %v", i+1, p.Name, err)
+			}
+		case name == "PRODID":
+			cal.ProdID = unescape(p.Value)
+		case name == "VERSION":
+			// always written as 2.0
+		default:
+			cal.Other = append(cal.Other, p)
+		}
+	}
+	return nil, fmt.Errorf("ical: unexpected end of input")
+}
+
+func (e *Event) setProperty(name string, p Property) error {
+	var err error
+	switch name {
+	case "UID":
+		e.UID = unescape(p.Value)
+	case "SUMMARY":
+		e.Summary = unescape(p.Value)
+	case "DESCRIPTION":
+		e.Description = unescape(p.Value)
+	case "LOCATION":
+		e.Location = unescape(p.Value)
+	case "DTSTART":
+		e.Start, err = parseValue(p)
+	case "DTEND":
+		e.End, err = parseValue(p)
+	case "DUE":
+		e.Due, err = parseValue(p)
+	default:
+		e.Other = append(e.Other, p)
+	}
+	return err
+}
+
+// unfold reads the content lines from r, joining folded lines.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLineLength)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseLine parses an unfolded content line.
+func parseLine(line string) (Property, error) {
+	var p Property
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, fmt.Errorf("invalid content line %q", line)
+	}
+	p.Name, line = line[:i], line[i:]
+	for line[0] == ';' {
+		line = line[1:]
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return p, fmt.Errorf("invalid parameter in %s", p.Name)
+		}
+		param := Param{Name: line[:eq]}
+		line = line[eq+1:]
+		end, quoted := -1, false
+		for j := 0; j < len(line) && end < 0; j++ {
+			switch line[j] {
+			case '"':
+				quoted = !quoted
+			case ';', ':':
+				if !quoted {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			return p, fmt.Errorf("missing value in %s", p.Name)
+		}
+		param.Value, line = unquote(line[:end]), line[end:]
+		p.Params = append(p.Params, param)
+	}
+	p.Value = line[1:]
+	return p, nil
+}
+
+// unquote removes the quotes from a parameter value that is a single
+// quoted string.
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && strings.IndexByte(s[1:], '"') == len(s)-2 {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// unescape unescapes a TEXT value.
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				c = '\n'
+			default:
+				c = s[i]
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxOctets is the longest a content line may be before it is folded.
+const maxOctets = 75
+
+// DefaultProdID is written as the PRODID of a calendar that does not have one.
+const DefaultProdID = "-//jjeffery//civil ical//EN"
+
+// Write writes the calendar to w, with lines separated by CRLF and
+// folded at 75 octets.
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	write := func(p Property) {
+		writeFolded(bw, p.String())
+	}
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = DefaultProdID
+	}
+	write(Property{Name: "BEGIN", Value: "VCALENDAR"})
+	write(Property{Name: "VERSION", Value: "2.0"})
+	write(Property{Name: "PRODID", Value: escape(prodID)})
+	for _, p := range c.Other {
+		write(p)
+	}
+	for _, e := range c.Events {
+		component := e.Component
+		if component == "" {
+			component = "VEVENT"
+		}
+		write(Property{Name: "BEGIN", Value: component})
+		if e.UID != "" {
+			write(Property{Name: "UID", Value: escape(e.UID)})
+		}
+		if !e.Start.IsZero() {
+			write(e.Start.property("DTSTART"))
+		}
+		if !e.End.IsZero() {
+			write(e.End.property("DTEND"))
+		}
+		if !e.Due.IsZero() {
+			write(e.Due.property("DUE"))
+		}
+		if e.Summary != "" {
+			write(Property{Name: "SUMMARY", Value: escape(e.Summary)})
+		}
+		if e.Description != "" {
+			write(Property{Name: "DESCRIPTION", Value: escape(e.Description)})
+		}
+		if e.Location != "" {
+			write(Property{Name: "LOCATION", Value: escape(e.Location)})
+		}
+		for _, p := range e.Other {
+			write(p)
+		}
+		write(Property{Name: "END", Value: component})
+	}
+	write(Property{Name: "END", Value: "VCALENDAR"})
+	return bw.Flush()
+}
+
+// String returns the property as an unfolded content line.
+func (p Property) String() string {
+	s := p.Name
+	for _, param := range p.Params {
+		value := param.Value
+		if strings.ContainsAny(value, ";:,") && !strings.Contains(value, `"`) {
+			value = `"` + value + `"`
+		}
+		s += ";" + param.Name + "=" + value
+	}
+	return s + ":" + p.Value
+}
+
+// writeFolded writes line followed by CRLF, folding it so that no line is
+// longer than maxOctets, without splitting a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxOctets
+	for len(line) > limit {
+		n := limit
+		for n > 0 && !utf8.RuneStart(line[n]) {
+			n--
+		}
+		w.WriteString(line[:n])
+		w.WriteString("\r\n ")
+		line = line[n:]
+		// continuation lines start with a space
+		limit = maxOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
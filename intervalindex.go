@@ -0,0 +1,191 @@
+package civil
+
+import (
+	"sort"
+)
+
+// Interval associates a value with a range of civil date-times.
+type Interval struct {
+	Range DateTimeRange
+	Value interface{}
+}
+
+// IntervalIndex is an immutable index of intervals that answers
+// overlap, containment and point queries without scanning every
+// interval.
+//
+// The index is an augmented binary search tree laid out implicitly
+// over the intervals sorted by start. Each node records the latest end
+// of any interval in its subtree, which allows whole subtrees to be
+// skipped during a query. Because an IntervalIndex is never modified
+// after it is built, it is safe for concurrent use by multiple goroutines.
+//
+// Query results are returned in order of start, then end.
+type IntervalIndex struct {
+	intervals []Interval
+	starts    []int64
+	ends      []int64
+	maxEnds   []int64
+}
+
+// NewIntervalIndex builds an index containing the intervals. The intervals
+// slice is copied, so the caller is free to modify it afterwards.
+func NewIntervalIndex(intervals []Interval) *IntervalIndex {
+	n := len(intervals)
+	idx := &IntervalIndex{
+		intervals: make([]Interval, n),
+		starts:    make([]int64, n),
+		ends:      make([]int64, n),
+		maxEnds:   make([]int64, n),
+	}
+	copy(idx.intervals, intervals)
+	for i, iv := range idx.intervals {
+		idx.starts[i] = iv.Range.Start.Unix()
+		idx.ends[i] = iv.Range.End.Unix()
+	}
+	sort.Sort(byStartEnd{idx})
+	if n > 0 {
+		idx.build(0, n)
+	}
+	return idx
+}
+
+// byStartEnd sorts the intervals in an index by start, then end.
+type byStartEnd struct {
+	idx *IntervalIndex
+}
+
+func (s byStartEnd) Len() int {
+	return len(s.idx.intervals)
+}
+
+func (s byStartEnd) Less(i, j int) bool {
+	if s.idx.starts[i] == s.idx.starts[j] {
+		return s.idx.ends[i] < s.idx.ends[j]
+	}
+	return s.idx.starts[i] < s.idx.starts[j]
+}
+
+func (s byStartEnd) Swap(i, j int) {
+	s.idx.intervals[i], s.idx.intervals[j] = s.idx.intervals[j], s.idx.intervals[i]
+	s.idx.starts[i], s.idx.starts[j] = s.idx.starts[j], s.idx.starts[i]
+	s.idx.ends[i], s.idx.ends[j] = s.idx.ends[j], s.idx.ends[i]
+}
+
+// build calculates the maximum end for the subtree spanning [lo, hi),
+// which is stored at the subtree's root, and returns it.
+func (idx *IntervalIndex) build(lo, hi int) int64 {
+	mid := int(uint(lo+hi) >> 1)
+	maxEnd := idx.ends[mid]
+	if lo < mid {
+		if end := idx.build(lo, mid); end > maxEnd {
+			maxEnd = end
+		}
+	}
+	if mid+1 < hi {
+		if end := idx.build(mid+1, hi); end > maxEnd {
+			maxEnd = end
+		}
+	}
+	idx.maxEnds[mid] = maxEnd
+	return maxEnd
+}
+
+// With returns a new index containing the intervals in idx together
+// with the additional intervals. The original index is unchanged.
+func (idx *IntervalIndex) With(intervals ...Interval) *IntervalIndex {
+	all := make([]Interval, 0, idx.Len()+len(intervals))
+	all = append(all, idx.Intervals()...)
+	all = append(all, intervals...)
+	return NewIntervalIndex(all)
+}
+
+// Len returns the number of intervals in the index.
+func (idx *IntervalIndex) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.intervals)
+}
+
+// Intervals returns all of the intervals in the index.
+func (idx *IntervalIndex) Intervals() []Interval {
+	if idx == nil {
+		return nil
+	}
+	intervals := make([]Interval, len(idx.intervals))
+	copy(intervals, idx.intervals)
+	return intervals
+}
+
+// Overlapping returns the intervals that have at least one
+// date-time in common with r.
+func (idx *IntervalIndex) Overlapping(r DateTimeRange) []Interval {
+	if r.IsEmpty() {
+		return nil
+	}
+	// start < r.End && end > r.Start
+	return idx.query(r.End.Unix()-1, r.Start.Unix()+1)
+}
+
+// At returns the intervals that contain dt.
+func (idx *IntervalIndex) At(dt DateTime) []Interval {
+	// start <= dt && end > dt
+	t := dt.Unix()
+	return idx.query(t, t+1)
+}
+
+// Containing returns the intervals that contain every date-time in r.
+func (idx *IntervalIndex) Containing(r DateTimeRange) []Interval {
+	// start <= r.Start && end >= r.End
+	return idx.query(r.Start.Unix(), r.End.Unix())
+}
+
+// Within returns the non-empty intervals that are entirely contained by r.
+func (idx *IntervalIndex) Within(r DateTimeRange) []Interval {
+	if idx == nil || r.IsEmpty() {
+		return nil
+	}
+	start, end := r.Start.Unix(), r.End.Unix()
+	lo := sort.Search(len(idx.starts), func(i int) bool {
+		return idx.starts[i] >= start
+	})
+	var result []Interval
+	for i := lo; i < len(idx.starts) && idx.starts[i] < end; i++ {
+		if idx.ends[i] <= end && idx.ends[i] > idx.starts[i] {
+			result = append(result, idx.intervals[i])
+		}
+	}
+	return result
+}
+
+// query returns the non-empty intervals whose start is not after maxStart
+// and whose end is not before minEnd.
+func (idx *IntervalIndex) query(maxStart, minEnd int64) []Interval {
+	if idx == nil {
+		return nil
+	}
+	var result []Interval
+	idx.search(0, len(idx.intervals), maxStart, minEnd, &result)
+	return result
+}
+
+func (idx *IntervalIndex) search(lo, hi int, maxStart, minEnd int64, result *[]Interval) {
+	if lo >= hi {
+		return
+	}
+	mid := int(uint(lo+hi) >> 1)
+	if idx.maxEnds[mid] < minEnd {
+		// nothing in this subtree ends late enough
+		return
+	}
+	idx.search(lo, mid, maxStart, minEnd, result)
+	if idx.starts[mid] > maxStart {
+		// this node and everything to its right starts too late
+		return
+	}
+	if idx.ends[mid] >= minEnd && idx.ends[mid] > idx.starts[mid] {
+		*result = append(*result, idx.intervals[mid])
+	}
+	idx.search(mid+1, hi, maxStart, minEnd, result)
+}
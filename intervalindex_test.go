@@ -0,0 +1,183 @@
+package civil
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomIntervals(rnd *rand.Rand, n int) []Interval {
+	base := DateTimeFor(2021, 1, 1, 0, 0, 0)
+	intervals := make([]Interval, n)
+	for i := range intervals {
+		start := base.Add(time.Duration(rnd.Intn(365*24*60)) * time.Minute)
+		end := start.Add(time.Duration(rnd.Intn(12*60)) * time.Minute)
+		intervals[i] = Interval{Range: DateTimeRangeFor(start, end), Value: i}
+	}
+	return intervals
+}
+
+func randomRange(rnd *rand.Rand) DateTimeRange {
+	base := DateTimeFor(2021, 1, 1, 0, 0, 0)
+	start := base.Add(time.Duration(rnd.Intn(365*24*60)) * time.Minute)
+	end := start.Add(time.Duration(rnd.Intn(24*60)) * time.Minute)
+	return DateTimeRangeFor(start, end)
+}
+
+// scanIntervals is the naive linear scan used to check the index.
+func scanIntervals(intervals []Interval, match func(r DateTimeRange) bool) map[int]bool {
+	values := make(map[int]bool)
+	for _, iv := range intervals {
+		if match(iv.Range) {
+			values[iv.Value.(int)] = true
+		}
+	}
+	return values
+}
+
+func intervalValues(intervals []Interval) map[int]bool {
+	values := make(map[int]bool)
+	for _, iv := range intervals {
+		values[iv.Value.(int)] = true
+	}
+	return values
+}
+
+func TestIntervalIndexQueries(t *testing.T) {
+	assert := assert.New(t)
+	rnd := rand.New(rand.NewSource(1))
+	intervals := randomIntervals(rnd, 2000)
+	idx := NewIntervalIndex(intervals)
+	assert.Equal(len(intervals), idx.Len())
+
+	for i := 0; i < 200; i++ {
+		q := randomRange(rnd)
+
+		overlapping := idx.Overlapping(q)
+		assert.Equal(scanIntervals(intervals, q.Overlaps), intervalValues(overlapping), q.String())
+
+		containing := idx.Containing(q)
+		assert.Equal(scanIntervals(intervals, func(r DateTimeRange) bool {
+			return !r.IsEmpty() && r.ContainsRange(q)
+		}), intervalValues(containing), q.String())
+
+		within := idx.Within(q)
+		assert.Equal(scanIntervals(intervals, func(r DateTimeRange) bool {
+			return !r.IsEmpty() && q.ContainsRange(r)
+		}), intervalValues(within), q.String())
+
+		at := idx.At(q.Start)
+		assert.Equal(scanIntervals(intervals, func(r DateTimeRange) bool {
+			return r.Contains(q.Start)
+		}), intervalValues(at), q.Start.String())
+
+		// results are ordered by start
+		for j := 1; j < len(overlapping); j++ {
+			assert.False(overlapping[j].Range.Start.Before(overlapping[j-1].Range.Start))
+		}
+	}
+}
+
+func TestIntervalIndexBoundaries(t *testing.T) {
+	assert := assert.New(t)
+	rangeFor := func(start, end string) DateTimeRange {
+		return DateTimeRangeFor(mustParseDateTime(start), mustParseDateTime(end))
+	}
+	idx := NewIntervalIndex([]Interval{
+		{Range: rangeFor("2021-03-01T09:00", "2021-03-01T10:00"), Value: "a"},
+		{Range: rangeFor("2021-03-01T10:00", "2021-03-01T11:00"), Value: "b"},
+		{Range: rangeFor("2021-03-01T10:30", "2021-03-01T10:30"), Value: "empty"},
+	})
+
+	values := func(intervals []Interval) []interface{} {
+		var v []interface{}
+		for _, iv := range intervals {
+			v = append(v, iv.Value)
+		}
+		return v
+	}
+
+	assert.Equal([]interface{}{"b"}, values(idx.At(mustParseDateTime("2021-03-01T10:00"))))
+	assert.Equal([]interface{}{"a"}, values(idx.At(mustParseDateTime("2021-03-01T09:59:59"))))
+	assert.Equal([]interface{}{"b"}, values(idx.At(mustParseDateTime("2021-03-01T10:30"))))
+	assert.Nil(idx.At(mustParseDateTime("2021-03-01T11:00")))
+	assert.Equal([]interface{}{"a", "b"}, values(idx.Overlapping(rangeFor("2021-03-01T09:30", "2021-03-01T10:30"))))
+	assert.Nil(idx.Overlapping(rangeFor("2021-03-01T11:00", "2021-03-01T12:00")))
+	assert.Equal([]interface{}{"a", "b"}, values(idx.Within(rangeFor("2021-03-01T09:00", "2021-03-01T11:00"))))
+
+	idx2 := idx.With(Interval{Range: rangeFor("2021-03-01T08:00", "2021-03-01T12:00"), Value: "c"})
+	assert.Equal(3, idx.Len())
+	assert.Equal(4, idx2.Len())
+	assert.Equal([]interface{}{"c", "b"}, values(idx2.Containing(rangeFor("2021-03-01T10:15", "2021-03-01T10:45"))))
+
+	var empty *IntervalIndex
+	assert.Equal(0, empty.Len())
+	assert.Nil(empty.At(mustParseDateTime("2021-03-01T10:00")))
+	assert.Nil(NewIntervalIndex(nil).Overlapping(rangeFor("2021-03-01T09:30", "2021-03-01T10:30")))
+}
+
+func benchmarkIntervals(n int) ([]Interval, []DateTimeRange) {
+	rnd := rand.New(rand.NewSource(1))
+	intervals := randomIntervals(rnd, n)
+	queries := make([]DateTimeRange, 1000)
+	for i := range queries {
+		queries[i] = randomRange(rnd)
+	}
+	return intervals, queries
+}
+
+func BenchmarkIntervalIndexOverlapping(b *testing.B) {
+	intervals, queries := benchmarkIntervals(200000)
+	idx := NewIntervalIndex(intervals)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = idx.Overlapping(queries[i%len(queries)])
+	}
+}
+
+func BenchmarkIntervalScanOverlapping(b *testing.B) {
+	intervals, queries := benchmarkIntervals(200000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q := queries[i%len(queries)]
+		var result []Interval
+		for _, iv := range intervals {
+			if iv.Range.Start.Before(q.End) && q.Start.Before(iv.Range.End) {
+				result = append(result, iv)
+			}
+		}
+	}
+}
+
+func BenchmarkIntervalIndexAt(b *testing.B) {
+	intervals, queries := benchmarkIntervals(200000)
+	idx := NewIntervalIndex(intervals)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = idx.At(queries[i%len(queries)].Start)
+	}
+}
+
+func BenchmarkIntervalScanAt(b *testing.B) {
+	intervals, queries := benchmarkIntervals(200000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dt := queries[i%len(queries)].Start
+		var result []Interval
+		for _, iv := range intervals {
+			if !dt.Before(iv.Range.Start) && dt.Before(iv.Range.End) {
+				result = append(result, iv)
+			}
+		}
+	}
+}
+
+func BenchmarkNewIntervalIndex(b *testing.B) {
+	intervals, _ := benchmarkIntervals(200000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = NewIntervalIndex(intervals)
+	}
+}
@@ -0,0 +1,173 @@
+package civil
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errInvalidOffsetDateTimeFormat = errors.New("invalid offset date-time format")
+
+// OffsetDateTime represents a civil date-time together with a fixed
+// offset from UTC, as in "2021-03-01T10:00:00+10:00". Unlike a DateTime,
+// an OffsetDateTime identifies a unique instant in time, but it has no
+// time zone, so it cannot say what the offset will be at any other time.
+//
+// The offset is specified in seconds east of UTC and is truncated
+// to whole minutes, which is the precision of RFC 3339.
+type OffsetDateTime struct {
+	dt     DateTime
+	offset int
+}
+
+// OffsetDateTimeFor returns the OffsetDateTime with local date-time dt
+// and offset seconds east of UTC.
+func OffsetDateTimeFor(dt DateTime, offset int) OffsetDateTime {
+	return OffsetDateTime{dt: dt, offset: offset / 60 * 60}
+}
+
+// OffsetDateTimeOf returns the OffsetDateTime corresponding to t,
+// using the offset in effect in t's location at time t.
+func OffsetDateTimeOf(t time.Time) OffsetDateTime {
+	_, offset := t.Zone()
+	return OffsetDateTimeFor(DateTimeOf(t), offset)
+}
+
+// Local returns the civil date-time of o, without its offset.
+func (o OffsetDateTime) Local() DateTime {
+	return o.dt
+}
+
+// Offset returns the offset of o in seconds east of UTC.
+func (o OffsetDateTime) Offset() int {
+	return o.offset
+}
+
+// Instant returns the instant in time that o represents. The location
+// of the result is a fixed zone with the offset of o.
+func (o OffsetDateTime) Instant() time.Time {
+	year, month, day, hour, minute, second := o.dt.DateTime()
+	return time.Date(year, month, day, hour, minute, second, 0, time.FixedZone("", o.offset))
+}
+
+// WithOffsetSameInstant returns the OffsetDateTime for the same instant
+// as o, but with a different offset. The local date-time changes by the
+// difference between the two offsets.
+func (o OffsetDateTime) WithOffsetSameInstant(offset int) OffsetDateTime {
+	offset = offset / 60 * 60
+	return OffsetDateTime{
+		dt:     o.dt.Add(time.Duration(offset-o.offset) * time.Second),
+		offset: offset,
+	}
+}
+
+// WithOffsetSameLocal returns the OffsetDateTime with the same local
+// date-time as o, but with a different offset. The result represents
+// a different instant unless the offsets are equal.
+func (o OffsetDateTime) WithOffsetSameLocal(offset int) OffsetDateTime {
+	return OffsetDateTimeFor(o.dt, offset)
+}
+
+// Equal reports whether o and p have the same local date-time and the same
+// offset. To compare the instants they represent, use Instant().Equal.
+func (o OffsetDateTime) Equal(p OffsetDateTime) bool {
+	return o.dt.Equal(p.dt) && o.offset == p.offset
+}
+
+// Before reports whether the instant o is before the instant p.
+func (o OffsetDateTime) Before(p OffsetDateTime) bool {
+	return o.Instant().Before(p.Instant())
+}
+
+// After reports whether the instant o is after the instant p.
+func (o OffsetDateTime) After(p OffsetDateTime) bool {
+	return o.Instant().After(p.Instant())
+}
+
+// IsZero reports whether o represents the zero date-time with a zero offset.
+func (o OffsetDateTime) IsZero() bool {
+	return o.dt.IsZero() && o.offset == 0
+}
+
+// String returns a string representation of o in RFC 3339 format,
+// for example "2021-03-01T10:00:00+10:00". A zero offset is written as "Z".
+func (o OffsetDateTime) String() string {
+	return o.Instant().Format(time.RFC3339)
+}
+
+// ParseOffsetDateTime parses an RFC 3339 date-time with an offset, for
+// example "2021-03-01T10:00:00+10:00" or "2021-03-01T00:00:00Z". Leading and
+// trailing space and quotation marks are ignored. Fractional seconds
+// are accepted and discarded.
+func ParseOffsetDateTime(s string) (OffsetDateTime, error) {
+	s = strings.Trim(s, " \t\"'")
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return OffsetDateTime{}, errInvalidOffsetDateTimeFormat
+	}
+	return OffsetDateTimeOf(t), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The date-time is a quoted string in RFC 3339 format.
+func (o OffsetDateTime) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + o.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The date-time is expected to be a quoted string in RFC 3339 format.
+func (o *OffsetDateTime) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, nullText) {
+		*o = OffsetDateTime{}
+		return nil
+	}
+	*o, err = ParseOffsetDateTime(string(data))
+	return
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The date-time format is RFC 3339.
+func (o OffsetDateTime) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The date-time is expected to be in RFC 3339 format.
+func (o *OffsetDateTime) UnmarshalText(data []byte) (err error) {
+	*o, err = ParseOffsetDateTime(string(data))
+	return
+}
+
+// Scan implements the sql.Scanner interface.
+func (o *OffsetDateTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		o1, err := ParseOffsetDateTime(v)
+		if err != nil {
+			return err
+		}
+		*o = o1
+	case []byte:
+		o1, err := ParseOffsetDateTime(string(v))
+		if err != nil {
+			return err
+		}
+		*o = o1
+	case time.Time:
+		*o = OffsetDateTimeOf(v)
+	case nil:
+		*o = OffsetDateTime{}
+	default:
+		return errors.New("cannot convert to civil.OffsetDateTime")
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface. The value is an
+// RFC 3339 string rather than a time.Time, because many databases
+// store a time.Time in UTC and discard its offset.
+func (o OffsetDateTime) Value() (driver.Value, error) {
+	return o.String(), nil
+}
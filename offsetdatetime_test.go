@@ -0,0 +1,113 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetDateTime(t *testing.T) {
+	assert := assert.New(t)
+	o := OffsetDateTimeFor(DateTimeFor(2021, 3, 1, 10, 0, 0), 10*3600)
+
+	assert.Equal("2021-03-01T10:00:00", o.Local().String())
+	assert.Equal(36000, o.Offset())
+	assert.Equal("2021-03-01T10:00:00+10:00", o.String())
+	assert.True(o.Instant().Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	utc := o.WithOffsetSameInstant(0)
+	assert.Equal("2021-03-01T00:00:00Z", utc.String())
+	assert.True(utc.Instant().Equal(o.Instant()))
+	assert.False(utc.Equal(o))
+
+	ist := o.WithOffsetSameInstant(5*3600 + 30*60)
+	assert.Equal("2021-03-01T05:30:00+05:30", ist.String())
+
+	same := o.WithOffsetSameLocal(-5 * 3600)
+	assert.Equal("2021-03-01T10:00:00-05:00", same.String())
+	assert.True(o.Before(same))
+	assert.True(same.After(o))
+	assert.False(o.After(utc))
+	assert.False(o.Before(utc))
+
+	// offsets are truncated to whole minutes
+	assert.Equal(600, OffsetDateTimeFor(o.Local(), 630).Offset())
+
+	assert.True(OffsetDateTime{}.IsZero())
+	assert.False(o.IsZero())
+
+	loc := time.FixedZone("AEST", 10*3600)
+	o2 := OffsetDateTimeOf(time.Date(2021, 3, 1, 10, 0, 0, 0, loc))
+	assert.True(o.Equal(o2))
+}
+
+func TestParseOffsetDateTime(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Text     string
+		Expected string
+		Error    bool
+	}{
+		{Text: "2021-03-01T10:00:00+10:00", Expected: "2021-03-01T10:00:00+10:00"},
+		{Text: "2021-03-01T10:00:00-03:30", Expected: "2021-03-01T10:00:00-03:30"},
+		{Text: "2021-03-01T10:00:00Z", Expected: "2021-03-01T10:00:00Z"},
+		{Text: "2021-03-01T10:00:00.123+10:00", Expected: "2021-03-01T10:00:00+10:00"},
+		{Text: ` "2021-03-01T10:00:00+10:00" `, Expected: "2021-03-01T10:00:00+10:00"},
+		{Text: "2021-03-01T10:00:00", Error: true},
+		{Text: "2021-03-01", Error: true},
+		{Text: "xxx", Error: true},
+	}
+
+	for _, tc := range testCases {
+		o, err := ParseOffsetDateTime(tc.Text)
+		if tc.Error {
+			assert.Error(err, tc.Text)
+			continue
+		}
+		assert.NoError(err, tc.Text)
+		assert.Equal(tc.Expected, o.String(), tc.Text)
+	}
+}
+
+func TestOffsetDateTimeEncoding(t *testing.T) {
+	assert := assert.New(t)
+	o := OffsetDateTimeFor(DateTimeFor(2021, 3, 1, 10, 0, 0), 10*3600)
+
+	data, err := json.Marshal(o)
+	assert.NoError(err)
+	assert.Equal(`"2021-03-01T10:00:00+10:00"`, string(data))
+	var o2 OffsetDateTime
+	assert.NoError(json.Unmarshal(data, &o2))
+	assert.True(o.Equal(o2))
+	assert.NoError(json.Unmarshal([]byte("null"), &o2))
+	assert.True(o2.IsZero())
+	assert.Error(json.Unmarshal([]byte(`"2021-03-01"`), &o2))
+
+	text, err := o.MarshalText()
+	assert.NoError(err)
+	var o3 OffsetDateTime
+	assert.NoError(o3.UnmarshalText(text))
+	assert.True(o.Equal(o3))
+
+	v, err := o.Value()
+	assert.NoError(err)
+	assert.Equal("2021-03-01T10:00:00+10:00", v)
+
+	for _, src := range []interface{}{
+		"2021-03-01T10:00:00+10:00",
+		[]byte("2021-03-01T10:00:00+10:00"),
+		time.Date(2021, 3, 1, 10, 0, 0, 0, time.FixedZone("", 10*3600)),
+	} {
+		var o4 OffsetDateTime
+		assert.NoError(o4.Scan(src))
+		assert.True(o.Equal(o4), o4.String())
+	}
+	var o5 OffsetDateTime
+	assert.NoError(o5.Scan(nil))
+	assert.True(o5.IsZero())
+	assert.Error(o5.Scan("2021"))
+	assert.Error(o5.Scan([]byte("2021")))
+	assert.Error(o5.Scan(int64(1)))
+}
@@ -0,0 +1,358 @@
+package civil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidTimeOfDayRange = errors.New("invalid opening hours: times must be whole minutes within the day")
+	errEmptyTimeOfDayRange   = errors.New("invalid opening hours: a range cannot end when it starts, or start at 24:00")
+)
+
+// maxOpeningHoursSearch is the number of days, about ten years, searched for
+// the next opening or closing time before giving up.
+const maxOpeningHoursSearch = 3660
+
+// OpeningRule sets the opening times for days of the week, or for a date.
+type OpeningRule struct {
+	// Days are the days of the week that the rule applies to, if Date is zero.
+	// If both Days and Date are zero, the rule applies to every day.
+	Days Weekdays
+
+	// Date is the date that the rule applies to. It is zero for a rule that
+	// applies every week.
+	Date Date
+
+	// Times are the opening times. If empty, the rule is for days that are
+	// closed.
+	Times []TimeOfDayRange
+}
+
+// OpeningHours are the times that a place such as a shop is open each week,
+// with exceptions for particular dates, such as a public holiday or late night
+// trading before Christmas. Opening times that continue past midnight are
+// supported.
+//
+// OpeningHours have a compact text format that is compatible with the basic
+// parts of the OpenStreetMap opening_hours tag:
+//
+//	Mo-Fr 09:00-17:30; Sa 10:00-14:00; 2021 Dec 25 off; 2021 Dec 23 09:00-21:00
+//
+// An OpeningHours is immutable, and is safe for concurrent use by multiple
+// goroutines.
+type OpeningHours struct {
+	rules  []OpeningRule
+	weekly [7][]TimeOfDayRange
+	dates  map[Date][]TimeOfDayRange
+}
+
+// NewOpeningHours returns the opening hours for the rules. Rules for dates
+// take precedence over rules for days of the week, and a later rule takes
+// precedence over an earlier rule for the same day. So "Mo-Sa 09:00-17:00;
+// Sa 10:00-14:00" is open from 10:00 to 14:00 on Saturday.
+func NewOpeningHours(rules ...OpeningRule) (*OpeningHours, error) {
+	h := &OpeningHours{dates: make(map[Date][]TimeOfDayRange)}
+	for _, rule := range rules {
+		times := append([]TimeOfDayRange(nil), rule.Times...)
+		for _, t := range times {
+			if !isTimeOfDay(t.Start) || !isTimeOfDay(t.End) {
+				return nil, errInvalidTimeOfDayRange
+			}
+			if t.Start == t.End || t.Start == 24*time.Hour {
+				return nil, errEmptyTimeOfDayRange
+			}
+		}
+		sort.Slice(times, func(i, j int) bool {
+			return times[i].Start < times[j].Start
+		})
+		rule.Times = times
+		switch {
+		case !rule.Date.IsZero():
+			rule.Days = NoWeekdays
+			h.dates[rule.Date] = times
+		case rule.Days.IsEmpty():
+			rule.Days = AllWeekdays
+			fallthrough
+		default:
+			for _, day := range rule.Days.Days(time.Sunday) {
+				h.weekly[day] = times
+			}
+		}
+		h.rules = append(h.rules, rule)
+	}
+	return h, nil
+}
+
+// isTimeOfDay reports whether d is a whole number of minutes from 00:00 to 24:00.
+func isTimeOfDay(d time.Duration) bool {
+	return d >= 0 && d <= 24*time.Hour && d%time.Minute == 0
+}
+
+// ParseOpeningHours parses opening hours in the text format, which is a list
+// of rules separated by semicolons. Each rule has an optional selector
+// followed by a list of times separated by commas, or "off" for closed.
+// The selector is a list of days of the week, such as "Mo-Fr" or "Mo,We,Fr",
+// or a date, such as "2021 Dec 25". A rule without a selector applies to
+// every day, and "24/7" is open all the time.
+func ParseOpeningHours(s string) (*OpeningHours, error) {
+	var rules []OpeningRule
+	for _, text := range strings.Split(s, ";") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		rule, err := parseOpeningRule(text)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return NewOpeningHours(rules...)
+}
+
+func parseOpeningRule(text string) (OpeningRule, error) {
+	var rule OpeningRule
+	invalid := fmt.Errorf("invalid opening hours rule %q", text)
+	if text == "24/7" {
+		rule.Times = []TimeOfDayRange{{End: 24 * time.Hour}}
+		return rule, nil
+	}
+	fields := strings.Fields(text)
+	switch {
+	case len(fields) >= 4 && len(fields[0]) == 4:
+		year, err1 := strconv.Atoi(fields[0])
+		month, ok := lookupMonth(fields[1])
+		day, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || !ok || day < 1 || day > 31 {
+			return rule, invalid
+		}
+		rule.Date = DateFor(year, month, day)
+		if rule.Date.Day() != day {
+			return rule, invalid
+		}
+		fields = fields[3:]
+	case len(fields) >= 2 && !strings.Contains(fields[0], ":"):
+		days, err := ParseWeekdays(fields[0])
+		if err != nil || days.IsEmpty() {
+			return rule, invalid
+		}
+		rule.Days = days
+		fields = fields[1:]
+	}
+	// times may be separated by a comma and a space
+	times := strings.Join(fields, "")
+	if lower := strings.ToLower(times); lower == "off" || lower == "closed" {
+		return rule, nil
+	}
+	for _, part := range strings.Split(times, ",") {
+		hyphen := strings.IndexByte(part, '-')
+		if hyphen < 0 {
+			return rule, invalid
+		}
+		start, ok1 := parseTimeOfDay(part[:hyphen])
+		end, ok2 := parseTimeOfDay(part[hyphen+1:])
+		if !ok1 || !ok2 {
+			return rule, invalid
+		}
+		rule.Times = append(rule.Times, TimeOfDayRange{Start: start, End: end})
+	}
+	return rule, nil
+}
+
+// parseTimeOfDay parses a time of day in the format "hh:mm", from 00:00 to 24:00.
+func parseTimeOfDay(s string) (time.Duration, bool) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, false
+	}
+	hour, err1 := strconv.Atoi(s[:2])
+	minute, err2 := strconv.Atoi(s[3:])
+	if err1 != nil || err2 != nil || minute < 0 || minute > 59 || hour < 0 || hour > 24 || hour == 24 && minute > 0 {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// lookupMonth returns the month with the three letter abbreviation name.
+func lookupMonth(name string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		if strings.EqualFold(name, month.String()[:3]) {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the opening hours in the text format accepted by
+// ParseOpeningHours.
+func (h OpeningHours) String() string {
+	var parts []string
+	for _, rule := range h.rules {
+		if rule.Days == AllWeekdays && len(rule.Times) == 1 && rule.Times[0] == (TimeOfDayRange{End: 24 * time.Hour}) {
+			parts = append(parts, "24/7")
+			continue
+		}
+		var selector string
+		if rule.Date.IsZero() {
+			selector = rule.Days.format(func(day time.Weekday) string {
+				return day.String()[:2]
+			})
+		} else {
+			selector = rule.Date.Format("2006 Jan 02")
+		}
+		times := "off"
+		if len(rule.Times) > 0 {
+			var ranges []string
+			for _, t := range rule.Times {
+				ranges = append(ranges, t.String())
+			}
+			times = strings.Join(ranges, ",")
+		}
+		parts = append(parts, selector+" "+times)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (h OpeningHours) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (h *OpeningHours) UnmarshalText(data []byte) error {
+	h1, err := ParseOpeningHours(string(data))
+	if err != nil {
+		return err
+	}
+	*h = *h1
+	return nil
+}
+
+// IsOpen reports whether dt is within the opening hours.
+func (h *OpeningHours) IsOpen(dt DateTime) bool {
+	date := DateFor(dt.Date())
+	for _, d := range []Date{date.AddDate(0, 0, -1), date} {
+		for _, r := range h.startsOn(d) {
+			if r.Contains(dt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NextOpen returns the first date-time at or after dt that is within the
+// opening hours, which is dt if it is open. It returns the zero DateTime and
+// false if there are no opening hours in the next ten years.
+func (h *OpeningHours) NextOpen(dt DateTime) (DateTime, bool) {
+	var next DateTime
+	found := false
+	h.each(DateFor(dt.Date()).AddDate(0, 0, -1), maxOpeningHoursSearch, func(r DateTimeRange) bool {
+		if r.End.After(dt) {
+			next, found = r.Start, true
+			if next.Before(dt) {
+				next = dt
+			}
+		}
+		return !found
+	})
+	return next, found
+}
+
+// NextClose returns the first date-time at or after dt that is not within
+// the opening hours, which is dt if it is closed. It returns the zero
+// DateTime and false if the opening hours do not close in the next ten years.
+func (h *OpeningHours) NextClose(dt DateTime) (DateTime, bool) {
+	if !h.IsOpen(dt) {
+		return dt, true
+	}
+	start := DateFor(dt.Date()).AddDate(0, 0, -1)
+	year, month, day := start.AddDate(0, 0, maxOpeningHoursSearch).Date()
+	horizon := DateTimeFor(year, month, day, 0, 0, 0)
+	var next DateTime
+	found := false
+	h.each(start, maxOpeningHoursSearch, func(r DateTimeRange) bool {
+		if r.Contains(dt) {
+			// an interval that reaches the horizon may not end there
+			if r.End.Before(horizon) {
+				next, found = r.End, true
+			}
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// OpenIntervals returns the times that are within the opening hours on the
+// dates in r, in order. Opening times that continue past midnight at the
+// end of r are cut off at midnight.
+func (h *OpeningHours) OpenIntervals(r DateRange) []DateTimeRange {
+	if r.IsEmpty() {
+		return nil
+	}
+	year, month, day := r.Start.Date()
+	span := DateTimeRange{Start: DateTimeFor(year, month, day, 0, 0, 0)}
+	year, month, day = r.End.Date()
+	span.End = DateTimeFor(year, month, day+1, 0, 0, 0)
+
+	var intervals []DateTimeRange
+	h.each(r.Start.AddDate(0, 0, -1), r.Days()+1, func(open DateTimeRange) bool {
+		if open = open.Intersect(span); !open.IsEmpty() {
+			intervals = append(intervals, open)
+		}
+		return true
+	})
+	return intervals
+}
+
+// each calls fn with the opening times that start within the days beginning
+// with from, in order. Opening times that overlap or touch are combined.
+// It stops if fn returns false.
+func (h *OpeningHours) each(from Date, days int, fn func(r DateTimeRange) bool) {
+	var current DateTimeRange
+	started := false
+	for i := 0; i < days; i++ {
+		for _, r := range h.startsOn(from.AddDate(0, 0, i)) {
+			if started && !r.Start.After(current.End) {
+				if r.End.After(current.End) {
+					current.End = r.End
+				}
+				continue
+			}
+			if started && !fn(current) {
+				return
+			}
+			current, started = r, true
+		}
+	}
+	if started {
+		fn(current)
+	}
+}
+
+// startsOn returns the opening times that start on date.
+func (h *OpeningHours) startsOn(date Date) []DateTimeRange {
+	times, ok := h.dates[date]
+	if !ok {
+		times = h.weekly[date.Weekday()]
+	}
+	if len(times) == 0 {
+		return nil
+	}
+	year, month, day := date.Date()
+	midnight := DateTimeFor(year, month, day, 0, 0, 0)
+	ranges := make([]DateTimeRange, len(times))
+	for i, t := range times {
+		end := t.End
+		if end <= t.Start {
+			end += 24 * time.Hour
+		}
+		ranges[i] = DateTimeRange{Start: midnight.Add(t.Start), End: midnight.Add(end)}
+	}
+	return ranges
+}
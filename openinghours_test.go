@@ -0,0 +1,177 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// a later rule for the same day replaces an earlier one
+const testOpeningHours = "Mo-Fr 09:00-17:30; Sa 10:00-14:00; Fr 09:00-17:30, 22:00-02:00; 2021 Dec 25 off; 2021 Dec 23 09:00-21:00"
+
+func TestParseOpeningHours(t *testing.T) {
+	h, err := ParseOpeningHours(testOpeningHours)
+	require.NoError(t, err)
+	assert.Equal(t, "Mo-Fr 09:00-17:30; Sa 10:00-14:00; Fr 09:00-17:30,22:00-02:00; 2021 Dec 25 off; 2021 Dec 23 09:00-21:00", h.String())
+
+	testCases := []struct {
+		Text     string
+		Expected string
+	}{
+		{"24/7", "24/7"},
+		{"  Mo-Su 00:00-24:00 ", "24/7"},
+		{"08:00-12:00, 13:00-17:00", "Mo-Su 08:00-12:00,13:00-17:00"},
+		{"mo,tu,we 13:00-17:00,08:00-12:00", "Mo-We 08:00-12:00,13:00-17:00"},
+		{"Su closed; 2021 jan 1 10:00-12:00", "Su off; 2021 Jan 01 10:00-12:00"},
+		{"Fr 18:00-00:00", "Fr 18:00-00:00"},
+	}
+	for _, tc := range testCases {
+		h, err := ParseOpeningHours(tc.Text)
+		if assert.NoError(t, err, tc.Text) {
+			assert.Equal(t, tc.Expected, h.String(), tc.Text)
+		}
+	}
+
+	for _, text := range []string{
+		"Mo-Fr",
+		"Xx 09:00-17:00",
+		"Mo-Fr 9:00-17:00",
+		"Mo-Fr 09:00",
+		"Mo-Fr 09:00-24:01",
+		"Mo-Fr 09:60-17:00",
+		"Mo-Fr 09:00-09:00",
+		"Mo-Fr 24:00-02:00",
+		"2021 Feb 30 off",
+		"2021 Foo 01 off",
+		"Mo Tu 09:00-17:00",
+	} {
+		_, err := ParseOpeningHours(text)
+		assert.Error(t, err, text)
+	}
+
+	_, err = NewOpeningHours(OpeningRule{Days: MondayToFriday, Times: []TimeOfDayRange{{Start: 9 * time.Hour, End: 17*time.Hour + time.Second}}})
+	assert.Equal(t, errInvalidTimeOfDayRange, err)
+	_, err = ParseOpeningHours("Mo 10:00-10:00")
+	assert.Equal(t, errEmptyTimeOfDayRange, err)
+	_, err = ParseOpeningHours("Mo 24:00-02:00")
+	assert.Equal(t, errEmptyTimeOfDayRange, err)
+}
+
+func TestOpeningHoursIsOpen(t *testing.T) {
+	h, err := ParseOpeningHours(testOpeningHours)
+	require.NoError(t, err)
+	testCases := []struct {
+		DateTime string
+		Open     bool
+	}{
+		{"2021-12-20T08:59", false}, // Monday
+		{"2021-12-20T09:00", true},
+		{"2021-12-20T17:29:59", true},
+		{"2021-12-20T17:30", false},
+		{"2021-12-23T20:00", true}, // late night
+		{"2021-12-24T23:00", true}, // Friday night
+		{"2021-12-25T01:00", true}, // continues past midnight into a closed day
+		{"2021-12-25T11:00", false},
+		{"2021-12-25T23:00", false},
+		{"2021-12-26T01:00", false},
+		{"2021-12-18T01:59", true}, // Saturday
+		{"2021-12-18T02:00", false},
+		{"2021-12-18T11:00", true},
+		{"2021-12-18T23:00", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.Open, h.IsOpen(mustParseDateTime(tc.DateTime)), tc.DateTime)
+	}
+}
+
+func TestOpeningHoursNext(t *testing.T) {
+	h, err := ParseOpeningHours(testOpeningHours)
+	require.NoError(t, err)
+	testCases := []struct {
+		DateTime  string
+		NextOpen  string
+		NextClose string
+	}{
+		{"2021-12-20T08:00", "2021-12-20T09:00:00", "2021-12-20T08:00:00"},
+		{"2021-12-20T10:00", "2021-12-20T10:00:00", "2021-12-20T17:30:00"},
+		{"2021-12-24T17:30", "2021-12-24T22:00:00", "2021-12-24T17:30:00"},
+		{"2021-12-24T23:00", "2021-12-24T23:00:00", "2021-12-25T02:00:00"},
+		{"2021-12-25T03:00", "2021-12-27T09:00:00", "2021-12-25T03:00:00"},
+	}
+	for _, tc := range testCases {
+		dt := mustParseDateTime(tc.DateTime)
+		next, ok := h.NextOpen(dt)
+		assert.True(t, ok, tc.DateTime)
+		assert.Equal(t, tc.NextOpen, next.String(), tc.DateTime)
+		next, ok = h.NextClose(dt)
+		assert.True(t, ok, tc.DateTime)
+		assert.Equal(t, tc.NextClose, next.String(), tc.DateTime)
+	}
+
+	always, err := ParseOpeningHours("24/7; 2021 Dec 25 off")
+	require.NoError(t, err)
+	next, ok := always.NextClose(mustParseDateTime("2021-12-20T10:00"))
+	assert.True(t, ok)
+	assert.Equal(t, "2021-12-25T00:00:00", next.String())
+	next, ok = always.NextClose(mustParseDateTime("2021-12-26T10:00"))
+	assert.False(t, ok)
+	assert.True(t, next.IsZero())
+
+	never, err := ParseOpeningHours("off")
+	require.NoError(t, err)
+	next, ok = never.NextOpen(mustParseDateTime("2021-12-20T10:00"))
+	assert.False(t, ok)
+	assert.True(t, next.IsZero())
+}
+
+func TestOpeningHoursIntervals(t *testing.T) {
+	h, err := ParseOpeningHours(testOpeningHours)
+	require.NoError(t, err)
+	var actual []string
+	for _, r := range h.OpenIntervals(DateRangeFor(DateFor(2021, 12, 23), DateFor(2021, 12, 26))) {
+		actual = append(actual, r.String())
+	}
+	assert.Equal(t, []string{
+		"2021-12-23T09:00:00/2021-12-23T21:00:00",
+		"2021-12-24T09:00:00/2021-12-24T17:30:00",
+		"2021-12-24T22:00:00/2021-12-25T02:00:00",
+	}, actual)
+
+	actual = nil
+	for _, r := range h.OpenIntervals(DateRangeFor(DateFor(2021, 12, 18), DateFor(2021, 12, 20))) {
+		actual = append(actual, r.String())
+	}
+	assert.Equal(t, []string{
+		"2021-12-18T00:00:00/2021-12-18T02:00:00",
+		"2021-12-18T10:00:00/2021-12-18T14:00:00",
+		"2021-12-20T09:00:00/2021-12-20T17:30:00",
+	}, actual)
+
+	assert.Empty(t, h.OpenIntervals(DateRangeFor(DateFor(2021, 12, 20), DateFor(2021, 12, 19))))
+
+	// adjacent days are combined
+	always, err := ParseOpeningHours("24/7")
+	require.NoError(t, err)
+	assert.Equal(t, []DateTimeRange{{
+		Start: mustParseDateTime("2021-12-20T00:00"),
+		End:   mustParseDateTime("2021-12-22T00:00"),
+	}}, always.OpenIntervals(DateRangeFor(DateFor(2021, 12, 20), DateFor(2021, 12, 21))))
+}
+
+func TestOpeningHoursJSON(t *testing.T) {
+	var v struct {
+		Hours *OpeningHours `json:"hours"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"hours":"Mo-Fr 09:00-17:00"}`), &v))
+	assert.True(t, v.Hours.IsOpen(mustParseDateTime("2021-12-20T10:00")))
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"hours":"Mo-Fr 09:00-17:00"}`, string(data))
+	data, err = json.Marshal(struct{ H OpeningHours }{*v.Hours})
+	require.NoError(t, err)
+	assert.Equal(t, `{"H":"Mo-Fr 09:00-17:00"}`, string(data))
+	assert.Error(t, json.Unmarshal([]byte(`{"hours":"bad"}`), &v))
+}
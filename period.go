@@ -0,0 +1,120 @@
+package civil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errInvalidPeriod = errors.New("invalid period format")
+
+// Period is an amount of calendar time in years, months and days, such as
+// the frequency of a payment schedule. Unlike a time.Duration, the length of
+// a period depends on the date that it is added to: one month after
+// 15 January is 15 February, and one month after 15 February is 15 March.
+type Period struct {
+	Years  int
+	Months int
+	Days   int
+}
+
+// PeriodOf returns the period of years, months and days.
+func PeriodOf(years, months, days int) Period {
+	return Period{Years: years, Months: months, Days: days}
+}
+
+// ParsePeriod parses an ISO 8601 period, such as "P1Y2M10D" or "P2W", or the
+// same without the leading "P", such as "3M" or "1Y6M", as is common for the
+// tenors of financial instruments. Weeks are converted to days.
+func ParsePeriod(s string) (Period, error) {
+	var p Period
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "P")
+	if s == "" {
+		return p, errInvalidPeriod
+	}
+	const units = "YMWD"
+	last := -1
+	for s != "" {
+		i := 0
+		if s[0] == '-' {
+			i++
+		}
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == len(s) {
+			return Period{}, errInvalidPeriod
+		}
+		n, err := strconv.Atoi(s[:i])
+		unit := strings.IndexByte(units, s[i])
+		if err != nil || unit <= last {
+			return Period{}, errInvalidPeriod
+		}
+		switch units[unit] {
+		case 'Y':
+			p.Years = n
+		case 'M':
+			p.Months = n
+		case 'W':
+			p.Days += 7 * n
+		case 'D':
+			p.Days += n
+		}
+		last = unit
+		s = s[i+1:]
+	}
+	return p, nil
+}
+
+// IsZero reports whether p is zero.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Negate returns p with the sign of each of its parts reversed.
+func (p Period) Negate() Period {
+	return Period{Years: -p.Years, Months: -p.Months, Days: -p.Days}
+}
+
+// Mul returns p with each of its parts multiplied by n.
+func (p Period) Mul(n int) Period {
+	return Period{Years: p.Years * n, Months: p.Months * n, Days: p.Days * n}
+}
+
+// String returns p in ISO 8601 format, such as "P1Y2M10D". A zero period
+// is "P0D".
+func (p Period) String() string {
+	if p.IsZero() {
+		return "P0D"
+	}
+	s := "P"
+	if p.Years != 0 {
+		s += strconv.Itoa(p.Years) + "Y"
+	}
+	if p.Months != 0 {
+		s += strconv.Itoa(p.Months) + "M"
+	}
+	if p.Days != 0 {
+		s += strconv.Itoa(p.Days) + "D"
+	}
+	return s
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (p Period) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (p *Period) UnmarshalText(data []byte) (err error) {
+	*p, err = ParsePeriod(string(data))
+	return
+}
+
+// AddPeriod returns the date p after d. It is the same as
+// d.AddDate(p.Years, p.Months, p.Days), and is normalized in
+// the same way.
+func (d Date) AddPeriod(p Period) Date {
+	return d.AddDate(p.Years, p.Months, p.Days)
+}
@@ -0,0 +1,67 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	testCases := []struct {
+		Text   string
+		Period Period
+		String string
+	}{
+		{"P1Y2M10D", PeriodOf(1, 2, 10), "P1Y2M10D"},
+		{"P2W", PeriodOf(0, 0, 14), "P14D"},
+		{"P1W3D", PeriodOf(0, 0, 10), "P10D"},
+		{"3M", PeriodOf(0, 3, 0), "P3M"},
+		{"1y6m", PeriodOf(1, 6, 0), "P1Y6M"},
+		{" 6M ", PeriodOf(0, 6, 0), "P6M"},
+		{"P-1M", PeriodOf(0, -1, 0), "P-1M"},
+		{"P0D", Period{}, "P0D"},
+	}
+	for _, tc := range testCases {
+		p, err := ParsePeriod(tc.Text)
+		if !assert.NoError(t, err, tc.Text) {
+			continue
+		}
+		assert.Equal(t, tc.Period, p, tc.Text)
+		assert.Equal(t, tc.String, p.String(), tc.Text)
+	}
+
+	for _, text := range []string{"", "P", "M", "3", "3X", "P1M1Y", "P1D1D", "P1.5Y", "P-M"} {
+		_, err := ParsePeriod(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestPeriodArithmetic(t *testing.T) {
+	assert := assert.New(t)
+	p := PeriodOf(1, 2, 3)
+	assert.False(p.IsZero())
+	assert.True(Period{}.IsZero())
+	assert.Equal(PeriodOf(-1, -2, -3), p.Negate())
+	assert.Equal(PeriodOf(2, 4, 6), p.Mul(2))
+
+	d := mustParseDate
+	assert.Equal(d("2021-04-15"), d("2021-01-15").AddPeriod(PeriodOf(0, 3, 0)))
+	assert.Equal(d("2022-03-18"), d("2021-01-15").AddPeriod(p))
+	assert.Equal(d("2020-12-15"), d("2021-01-15").AddPeriod(PeriodOf(0, -1, 0)))
+}
+
+func TestPeriodJSON(t *testing.T) {
+	assert := assert.New(t)
+	type Tenor struct {
+		Frequency Period `json:"frequency"`
+	}
+	data, err := json.Marshal(Tenor{Frequency: PeriodOf(0, 6, 0)})
+	assert.NoError(err)
+	assert.Equal(`{"frequency":"P6M"}`, string(data))
+
+	var tenor Tenor
+	assert.NoError(json.Unmarshal([]byte(`{"frequency":"1Y"}`), &tenor))
+	assert.Equal(PeriodOf(1, 0, 0), tenor.Frequency)
+	assert.Error(json.Unmarshal([]byte(`{"frequency":"1Q"}`), &tenor))
+}
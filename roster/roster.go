@@ -0,0 +1,321 @@
+// Package roster generates shifts from rotating roster patterns.
+//
+// A pattern has one code for each day of its cycle, such as "DDNN----" for
+// two day shifts, two night shifts and four days off. Teams work the same
+// pattern offset from each other, starting from an anchor date, and each
+// code has shift times, which may continue past midnight.
+package roster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Off is the code for a day off.
+const Off = '-'
+
+// On is the code used for working days in patterns given as "4 on, 4 off"
+// or "2-2-3".
+const On = 'D'
+
+var (
+	errNoTeams      = errors.New("roster: no teams")
+	errEmptyPattern = errors.New("roster: empty pattern")
+)
+
+// Pattern is the sequence of shift codes for each day of a roster cycle.
+type Pattern []rune
+
+// ParsePattern parses a pattern, which is one of:
+//
+//	DDNN----     one code for each day, where "-" or "." is a day off
+//	4 on, 4 off  days on and days off, with On as the code for days on
+//	2-2-3        alternating runs of days on and days off
+//
+// In the last form an odd number of runs is repeated, so that "2-2-3" is the
+// Pitman pattern of 2 on, 2 off, 3 on, 2 off, 2 on, 3 off. A single name
+// before the runs, as in "Pitman 2-2-3", is ignored.
+func ParsePattern(s string) (Pattern, error) {
+	invalid := fmt.Errorf("roster: invalid pattern %q", s)
+	fields := strings.Fields(strings.ToLower(strings.Replace(s, ",", " ", -1)))
+	switch {
+	case len(fields) == 4 && fields[1] == "on" && fields[3] == "off":
+		on, err1 := strconv.Atoi(fields[0])
+		off, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || on < 0 || off < 0 || on+off == 0 {
+			return nil, invalid
+		}
+		return runs([]int{on, off}), nil
+	case (len(fields) == 1 || len(fields) == 2) && strings.Contains(fields[len(fields)-1], "-") && isDigit(fields[len(fields)-1][0]):
+		var counts []int
+		for _, part := range strings.Split(fields[len(fields)-1], "-") {
+			n, err := strconv.Atoi(part)
+			if err != nil || n <= 0 {
+				return nil, invalid
+			}
+			counts = append(counts, n)
+		}
+		if len(counts)%2 != 0 {
+			counts = append(counts, counts...)
+		}
+		return runs(counts), nil
+	case len(fields) == 1:
+		var p Pattern
+		for _, code := range strings.TrimSpace(s) {
+			if code == '.' {
+				code = Off
+			}
+			p = append(p, code)
+		}
+		return p, nil
+	}
+	return nil, invalid
+}
+
+// isShiftTime reports whether t is a valid shift time, with a start and end
+// that are different whole minutes within the day. A shift that ends at
+// or before its start time continues past midnight.
+func isShiftTime(t civil.TimeOfDayRange) bool {
+	isTimeOfDay := func(d time.Duration) bool {
+		return d >= 0 && d <= 24*time.Hour && d%time.Minute == 0
+	}
+	return isTimeOfDay(t.Start) && isTimeOfDay(t.End) && t.Start != t.End && t.Start != 24*time.Hour
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// runs returns a pattern of alternating runs of days on and days off.
+func runs(counts []int) Pattern {
+	var p Pattern
+	for i, n := range counts {
+		code := rune(On)
+		if i%2 != 0 {
+			code = Off
+		}
+		for j := 0; j < n; j++ {
+			p = append(p, code)
+		}
+	}
+	return p
+}
+
+// String returns the pattern as one code for each day.
+func (p Pattern) String() string {
+	return string(p)
+}
+
+// Team is a team that works the roster pattern. A team with an Offset of n
+// works on each date the code that is n days further into the pattern than
+// a team with no offset.
+type Team struct {
+	Name   string
+	Offset int
+}
+
+// Shift is a shift worked by a team.
+type Shift struct {
+	Team  string
+	Date  civil.Date
+	Code  rune
+	Range civil.DateTimeRange
+}
+
+// Roster assigns shifts to teams according to a pattern.
+//
+// A Roster is immutable, and is safe for concurrent use by multiple goroutines.
+type Roster struct {
+	pattern   Pattern
+	anchor    civil.Date
+	shifts    map[rune]civil.TimeOfDayRange
+	teams     []Team
+	overrides map[override]rune
+}
+
+type override struct {
+	team string
+	date civil.Date
+}
+
+// New returns a roster where the first day of the pattern is the anchor
+// date. The shifts are the times of day of each code in the pattern,
+// and every code other than Off must have a shift time.
+func New(pattern Pattern, anchor civil.Date, shifts map[rune]civil.TimeOfDayRange, teams ...Team) (*Roster, error) {
+	if len(pattern) == 0 {
+		return nil, errEmptyPattern
+	}
+	if len(teams) == 0 {
+		return nil, errNoTeams
+	}
+	for _, code := range pattern {
+		if _, ok := shifts[code]; !ok && code != Off {
+			return nil, fmt.Errorf("roster: no shift time for code %q", code)
+		}
+	}
+	var codes []rune
+	for code := range shifts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	for _, code := range codes {
+		if t := shifts[code]; !isShiftTime(t) {
+			return nil, fmt.Errorf("roster: invalid shift time %s for code %q", t, code)
+		}
+	}
+	names := make(map[string]bool)
+	for _, team := range teams {
+		if names[team.Name] {
+			return nil, fmt.Errorf("roster: duplicate team %q", team.Name)
+		}
+		names[team.Name] = true
+	}
+	r := &Roster{
+		pattern:   append(Pattern(nil), pattern...),
+		anchor:    anchor,
+		shifts:    make(map[rune]civil.TimeOfDayRange),
+		teams:     append([]Team(nil), teams...),
+		overrides: make(map[override]rune),
+	}
+	for code, t := range shifts {
+		r.shifts[code] = t
+	}
+	return r, nil
+}
+
+// Code returns the code worked by the team on the date, which is Off for
+// a team that is not in the roster.
+func (r *Roster) Code(team string, date civil.Date) rune {
+	if code, ok := r.overrides[override{team: team, date: date}]; ok {
+		return code
+	}
+	for _, t := range r.teams {
+		if t.Name == team {
+			n := int((date.Unix()-r.anchor.Unix())/(24*60*60)) + t.Offset
+			n %= len(r.pattern)
+			if n < 0 {
+				n += len(r.pattern)
+			}
+			return r.pattern[n]
+		}
+	}
+	return Off
+}
+
+// WithOverride returns a roster where the team works code on the date
+// instead of the code in the pattern. Use Off for a day of leave. The
+// original roster is unchanged.
+func (r *Roster) WithOverride(team string, date civil.Date, code rune) (*Roster, error) {
+	if !r.hasTeam(team) {
+		return nil, fmt.Errorf("roster: unknown team %q", team)
+	}
+	if _, ok := r.shifts[code]; !ok && code != Off {
+		return nil, fmt.Errorf("roster: no shift time for code %q", code)
+	}
+	c := r.clone()
+	c.overrides[override{team: team, date: date}] = code
+	return c, nil
+}
+
+// WithSwap returns a roster where the two teams swap the codes they work on
+// the date. The original roster is unchanged.
+func (r *Roster) WithSwap(team1, team2 string, date civil.Date) (*Roster, error) {
+	for _, team := range []string{team1, team2} {
+		if !r.hasTeam(team) {
+			return nil, fmt.Errorf("roster: unknown team %q", team)
+		}
+	}
+	c := r.clone()
+	c.overrides[override{team: team1, date: date}] = r.Code(team2, date)
+	c.overrides[override{team: team2, date: date}] = r.Code(team1, date)
+	return c, nil
+}
+
+func (r *Roster) hasTeam(name string) bool {
+	for _, t := range r.teams {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Roster) clone() *Roster {
+	c := *r
+	c.overrides = make(map[override]rune, len(r.overrides)+2)
+	for k, v := range r.overrides {
+		c.overrides[k] = v
+	}
+	return &c
+}
+
+// Shifts returns the shifts that start on the dates in dr, in order of start
+// time, then in the order of the teams.
+func (r *Roster) Shifts(dr civil.DateRange) []Shift {
+	var shifts []Shift
+	for date := dr.Start; !date.After(dr.End); date = date.AddDate(0, 0, 1) {
+		shifts = append(shifts, r.shiftsOn(date)...)
+	}
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return shifts[i].Range.Start.Before(shifts[j].Range.Start)
+	})
+	return shifts
+}
+
+// OnAt returns the shifts being worked at dt, in the order of the teams.
+func (r *Roster) OnAt(dt civil.DateTime) []Shift {
+	var on []Shift
+	date := civil.DateFor(dt.Date())
+	// shifts that started the day before may continue past midnight
+	for _, s := range append(r.shiftsOn(date.AddDate(0, 0, -1)), r.shiftsOn(date)...) {
+		if s.Range.Contains(dt) {
+			on = append(on, s)
+		}
+	}
+	sort.SliceStable(on, func(i, j int) bool {
+		return r.teamIndex(on[i].Team) < r.teamIndex(on[j].Team)
+	})
+	return on
+}
+
+func (r *Roster) teamIndex(name string) int {
+	for i, t := range r.teams {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// shiftsOn returns the shifts that start on date, in the order of the teams.
+func (r *Roster) shiftsOn(date civil.Date) []Shift {
+	var shifts []Shift
+	year, month, day := date.Date()
+	midnight := civil.DateTimeFor(year, month, day, 0, 0, 0)
+	for _, team := range r.teams {
+		code := r.Code(team.Name, date)
+		if code == Off {
+			continue
+		}
+		t := r.shifts[code]
+		end := t.End
+		if end <= t.Start {
+			end += 24 * time.Hour
+		}
+		shifts = append(shifts, Shift{
+			Team:  team.Name,
+			Date:  date,
+			Code:  code,
+			Range: civil.DateTimeRange{Start: midnight.Add(t.Start), End: midnight.Add(end)},
+		})
+	}
+	return shifts
+}
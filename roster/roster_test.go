@@ -0,0 +1,146 @@
+package roster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseDateTime(s string) civil.DateTime {
+	dt, err := civil.ParseDateTime(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return dt
+}
+
+func TestParsePattern(t *testing.T) {
+	testCases := []struct {
+		Text     string
+		Expected string
+	}{
+		{"DDNN----", "DDNN----"},
+		{" DDNN.... ", "DDNN----"},
+		{"4 on, 4 off", "DDDD----"},
+		{"4 ON 3 OFF", "DDDD---"},
+		{"Pitman 2-2-3", "DD--DDD--DD---"},
+		{"2-2-3", "DD--DDD--DD---"},
+		{"5-2", "DDDDD--"},
+	}
+	for _, tc := range testCases {
+		p, err := ParsePattern(tc.Text)
+		if assert.NoError(t, err, tc.Text) {
+			assert.Equal(t, tc.Expected, p.String(), tc.Text)
+		}
+	}
+	for _, text := range []string{"", "DD NN", "x on, 4 off", "0 on 0 off", "2-0-3", "2-x", "DD NN 2-2", "Pitman rota 2-2-3"} {
+		_, err := ParsePattern(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func testRoster(t *testing.T) *Roster {
+	pattern, err := ParsePattern("DDNN----")
+	require.NoError(t, err)
+	r, err := New(pattern, civil.DateFor(2021, 3, 1), map[rune]civil.TimeOfDayRange{
+		'D': {Start: 7 * time.Hour, End: 19 * time.Hour},
+		'N': {Start: 19 * time.Hour, End: 7 * time.Hour},
+	},
+		Team{Name: "A"},
+		Team{Name: "B", Offset: 2},
+		Team{Name: "C", Offset: 4},
+		Team{Name: "D", Offset: 6},
+	)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRoster(t *testing.T) {
+	r := testRoster(t)
+	var codes string
+	for i := -2; i < 10; i++ {
+		codes += string(r.Code("A", civil.DateFor(2021, 3, 1).AddDate(0, 0, i)))
+	}
+	assert.Equal(t, "--DDNN----DD", codes)
+	assert.Equal(t, 'N', r.Code("B", civil.DateFor(2021, 3, 1)))
+	assert.Equal(t, rune(Off), r.Code("Z", civil.DateFor(2021, 3, 1)))
+
+	var actual []string
+	for _, s := range r.Shifts(civil.DateRangeFor(civil.DateFor(2021, 3, 1), civil.DateFor(2021, 3, 2))) {
+		actual = append(actual, s.Team+" "+string(s.Code)+" "+s.Range.String())
+	}
+	assert.Equal(t, []string{
+		"A D 2021-03-01T07:00:00/2021-03-01T19:00:00",
+		"B N 2021-03-01T19:00:00/2021-03-02T07:00:00",
+		"A D 2021-03-02T07:00:00/2021-03-02T19:00:00",
+		"B N 2021-03-02T19:00:00/2021-03-03T07:00:00",
+	}, actual)
+
+	// every hour is covered by exactly one team
+	start := mustParseDateTime("2021-03-01T00:00")
+	for i := 0; i < 24*16; i++ {
+		on := r.OnAt(start.Add(time.Duration(i) * time.Hour))
+		assert.Len(t, on, 1, "hour %d", i)
+	}
+	on := r.OnAt(mustParseDateTime("2021-03-01T03:00"))
+	require.Len(t, on, 1)
+	assert.Equal(t, "C", on[0].Team)
+	assert.Equal(t, civil.DateFor(2021, 2, 28), on[0].Date)
+}
+
+func TestOverridesAndSwaps(t *testing.T) {
+	r := testRoster(t)
+	date := civil.DateFor(2021, 3, 1)
+
+	leave, err := r.WithOverride("A", date, Off)
+	require.NoError(t, err)
+	assert.Empty(t, leave.OnAt(mustParseDateTime("2021-03-01T10:00")))
+	// the original is unchanged
+	assert.Len(t, r.OnAt(mustParseDateTime("2021-03-01T10:00")), 1)
+
+	cover, err := leave.WithOverride("C", date, 'D')
+	require.NoError(t, err)
+	on := cover.OnAt(mustParseDateTime("2021-03-01T10:00"))
+	require.Len(t, on, 1)
+	assert.Equal(t, "C", on[0].Team)
+
+	swapped, err := r.WithSwap("A", "B", date)
+	require.NoError(t, err)
+	assert.Equal(t, 'N', swapped.Code("A", date))
+	assert.Equal(t, 'D', swapped.Code("B", date))
+	assert.Equal(t, 'D', swapped.Code("A", date.AddDate(0, 0, 1)))
+
+	_, err = r.WithOverride("Z", date, Off)
+	assert.Error(t, err)
+	_, err = r.WithOverride("A", date, 'X')
+	assert.Error(t, err)
+	_, err = r.WithSwap("A", "Z", date)
+	assert.Error(t, err)
+}
+
+func TestNewErrors(t *testing.T) {
+	shifts := map[rune]civil.TimeOfDayRange{'D': {Start: 7 * time.Hour, End: 19 * time.Hour}}
+	anchor := civil.DateFor(2021, 3, 1)
+	_, err := New(nil, anchor, shifts, Team{Name: "A"})
+	assert.Error(t, err)
+	_, err = New(Pattern("DD--"), anchor, shifts)
+	assert.Error(t, err)
+	_, err = New(Pattern("DN--"), anchor, shifts, Team{Name: "A"})
+	assert.Error(t, err)
+	_, err = New(Pattern("DD--"), anchor, shifts, Team{Name: "A"}, Team{Name: "A", Offset: 2})
+	assert.Error(t, err)
+
+	for _, shift := range []civil.TimeOfDayRange{
+		{Start: -time.Hour, End: 7 * time.Hour},
+		{Start: 7 * time.Hour, End: 25 * time.Hour},
+		{Start: 7 * time.Hour, End: 19*time.Hour + time.Second},
+		{Start: 7 * time.Hour, End: 7 * time.Hour},
+		{Start: 24 * time.Hour, End: 7 * time.Hour},
+	} {
+		_, err = New(Pattern("DD--"), anchor, map[rune]civil.TimeOfDayRange{'D': shift}, Team{Name: "A"})
+		assert.Error(t, err, shift.String())
+	}
+}
@@ -0,0 +1,451 @@
+package rrule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// maxYear is the year after which expansion stops, so that a rule that can
+// never match, such as the 30th of February, does not iterate forever.
+const maxYear = 9999
+
+// Iterator iterates lazily over the occurrences of a rule or set, in order.
+type Iterator struct {
+	source interface {
+		next() (civil.DateTime, bool)
+	}
+}
+
+// Next returns the next occurrence, or false if there are no more.
+func (it *Iterator) Next() (civil.DateTime, bool) {
+	return it.source.next()
+}
+
+func (it *Iterator) all() []civil.DateTime {
+	var list []civil.DateTime
+	for dt, ok := it.Next(); ok; dt, ok = it.Next() {
+		list = append(list, dt)
+	}
+	return list
+}
+
+func (it *Iterator) between(a, b civil.DateTime, inclusive bool) []civil.DateTime {
+	var list []civil.DateTime
+	for dt, ok := it.Next(); ok; dt, ok = it.Next() {
+		if dt.After(b) || (!inclusive && dt.Equal(b)) {
+			break
+		}
+		if dt.After(a) || (inclusive && dt.Equal(a)) {
+			list = append(list, dt)
+		}
+	}
+	return list
+}
+
+func (it *Iterator) after(a civil.DateTime, inclusive bool) (civil.DateTime, bool) {
+	for dt, ok := it.Next(); ok; dt, ok = it.Next() {
+		if dt.After(a) || (inclusive && dt.Equal(a)) {
+			return dt, true
+		}
+	}
+	return civil.DateTime{}, false
+}
+
+func (it *Iterator) datesBetween(a, b civil.Date) []civil.Date {
+	var list []civil.Date
+	for dt, ok := it.Next(); ok; dt, ok = it.Next() {
+		d := dateOf(dt)
+		if d.After(b) {
+			break
+		}
+		if d.Before(a) {
+			continue
+		}
+		if n := len(list); n == 0 || !list[n-1].Equal(d) {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// ruleIterator expands a rule one period at a time. The period is a year,
+// month, week, day, hour, minute or second, depending on the frequency.
+type ruleIterator struct {
+	r      Rule // copy of the rule with defaults filled in
+	period civil.DateTime
+	buf    []civil.DateTime
+	count  int
+	done   bool
+}
+
+func newRuleIterator(rule *Rule) *ruleIterator {
+	it := &ruleIterator{r: *rule}
+	r := &it.r
+	if r.Interval < 1 {
+		r.Interval = 1
+	}
+	start := r.Start
+
+	// Rule parts that are not specified take their values from the start.
+	if len(r.ByWeekNo)+len(r.ByYearDay)+len(r.ByMonthDay)+len(r.ByDay) == 0 {
+		switch r.Freq {
+		case Yearly:
+			if len(r.ByMonth) == 0 {
+				r.ByMonth = []int{int(start.Month())}
+			}
+			r.ByMonthDay = []int{start.Day()}
+		case Monthly:
+			r.ByMonthDay = []int{start.Day()}
+		case Weekly:
+			r.ByDay = []WeekdayNum{{Weekday: start.Weekday()}}
+		}
+	}
+	if r.Freq < Hourly && len(r.ByHour) == 0 {
+		r.ByHour = []int{start.Hour()}
+	}
+	if r.Freq < Minutely && len(r.ByMinute) == 0 {
+		r.ByMinute = []int{start.Minute()}
+	}
+	if r.Freq < Secondly && len(r.BySecond) == 0 {
+		r.BySecond = []int{start.Second()}
+	}
+	r.ByHour = sortedCopy(r.ByHour)
+	r.ByMinute = sortedCopy(r.ByMinute)
+	r.BySecond = sortedCopy(r.BySecond)
+
+	d := dateOf(start)
+	year, month, _ := d.Date()
+	switch r.Freq {
+	case Yearly:
+		it.period = civil.DateTimeFor(year, time.January, 1, 0, 0, 0)
+	case Monthly:
+		it.period = civil.DateTimeFor(year, month, 1, 0, 0, 0)
+	case Weekly:
+		it.period = midnight(d.AddDate(0, 0, -daysSinceWeekStart(d, r.WeekStart)))
+	case Daily:
+		it.period = midnight(d)
+	case Hourly:
+		it.period = midnight(d).Add(time.Duration(start.Hour()) * time.Hour)
+	case Minutely:
+		it.period = midnight(d).Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+	default:
+		it.period = start
+	}
+	return it
+}
+
+func (it *ruleIterator) next() (civil.DateTime, bool) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return civil.DateTime{}, false
+		}
+		it.fill()
+	}
+	dt := it.buf[0]
+	it.buf = it.buf[1:]
+	if !it.r.Until.IsZero() && dt.After(it.r.Until) {
+		it.done, it.buf = true, nil
+		return civil.DateTime{}, false
+	}
+	it.count++
+	if it.r.Count > 0 && it.count >= it.r.Count {
+		it.done, it.buf = true, nil
+	}
+	return dt, true
+}
+
+// fill expands the current period into buf and moves to the next period.
+func (it *ruleIterator) fill() {
+	r := &it.r
+	p := it.period
+	if p.Year() > maxYear {
+		it.done = true
+		return
+	}
+
+	var times []civil.DateTime
+	if r.Freq <= Daily {
+		for _, d := range it.days() {
+			for _, h := range r.ByHour {
+				for _, m := range r.ByMinute {
+					for _, s := range r.BySecond {
+						times = append(times, midnight(d).Add(clock(h, m, s)))
+					}
+				}
+			}
+		}
+		it.advance()
+	} else {
+		// Skip quickly over days, hours and minutes that cannot match.
+		switch {
+		case !r.matchDay(dateOf(p)):
+			it.skipTo(midnight(dateOf(p).AddDate(0, 0, 1)))
+			return
+		case !matchInt(r.ByHour, p.Hour()):
+			it.skipTo(truncate(p, time.Hour).Add(time.Hour))
+			return
+		case r.Freq > Hourly && !matchInt(r.ByMinute, p.Minute()):
+			it.skipTo(truncate(p, time.Minute).Add(time.Minute))
+			return
+		}
+		switch r.Freq {
+		case Hourly:
+			for _, m := range r.ByMinute {
+				for _, s := range r.BySecond {
+					times = append(times, p.Add(clock(0, m, s)))
+				}
+			}
+		case Minutely:
+			for _, s := range r.BySecond {
+				times = append(times, p.Add(clock(0, 0, s)))
+			}
+		default:
+			if matchInt(r.BySecond, p.Second()) {
+				times = append(times, p)
+			}
+		}
+		it.advance()
+	}
+
+	if len(r.BySetPos) > 0 {
+		times = setPos(times, r.BySetPos)
+	}
+	for _, dt := range times {
+		if !dt.Before(r.Start) {
+			it.buf = append(it.buf, dt)
+		}
+	}
+}
+
+// advance moves to the next period.
+func (it *ruleIterator) advance() {
+	n := it.r.Interval
+	switch it.r.Freq {
+	case Yearly:
+		it.period = it.period.AddDate(n, 0, 0)
+	case Monthly:
+		it.period = it.period.AddDate(0, n, 0)
+	case Weekly:
+		it.period = it.period.AddDate(0, 0, 7*n)
+	case Daily:
+		it.period = it.period.AddDate(0, 0, n)
+	default:
+		it.period = it.period.Add(it.step())
+	}
+}
+
+// step returns the length of a period of a sub-daily rule.
+func (it *ruleIterator) step() time.Duration {
+	unit := time.Second
+	switch it.r.Freq {
+	case Hourly:
+		unit = time.Hour
+	case Minutely:
+		unit = time.Minute
+	}
+	return time.Duration(it.r.Interval) * unit
+}
+
+// skipTo moves a sub-daily rule to the first period at or after dt.
+func (it *ruleIterator) skipTo(dt civil.DateTime) {
+	step := it.step()
+	n := (dt.Sub(it.period) + step - 1) / step
+	if n < 1 {
+		n = 1
+	}
+	it.period = it.period.Add(n * step)
+}
+
+// days returns the dates in the current period that match the rule.
+func (it *ruleIterator) days() []civil.Date {
+	first := dateOf(it.period)
+	var last civil.Date
+	switch it.r.Freq {
+	case Yearly:
+		last = first.AddDate(1, 0, -1)
+	case Monthly:
+		last = first.AddDate(0, 1, -1)
+	case Weekly:
+		last = first.AddDate(0, 0, 6)
+	default:
+		last = first
+	}
+	var days []civil.Date
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if it.r.matchDay(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// matchDay reports whether d matches the date rule parts.
+func (r *Rule) matchDay(d civil.Date) bool {
+	year, month, day := d.Date()
+	if len(r.ByMonth) > 0 && !matchInt(r.ByMonth, int(month)) {
+		return false
+	}
+	if len(r.ByWeekNo) > 0 {
+		weekYear, week := weekNumber(d, r.WeekStart)
+		if !matchSigned(r.ByWeekNo, week, weeksInYear(weekYear, r.WeekStart)) {
+			return false
+		}
+	}
+	if len(r.ByYearDay) > 0 && !matchSigned(r.ByYearDay, d.YearDay(), daysInYear(year)) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !matchSigned(r.ByMonthDay, day, daysInMonth(year, month)) {
+		return false
+	}
+	if len(r.ByDay) > 0 {
+		inMonth := r.Freq == Monthly || len(r.ByMonth) > 0
+		matched := false
+		for _, wn := range r.ByDay {
+			if wn.Weekday != d.Weekday() {
+				continue
+			}
+			if wn.N == 0 {
+				matched = true
+			} else if inMonth {
+				matched = ordinalMatch(wn.N, day, daysInMonth(year, month))
+			} else {
+				matched = ordinalMatch(wn.N, d.YearDay(), daysInYear(year))
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ordinalMatch reports whether the weekday on day n of a month or year
+// of length days is the ordinal'th such weekday.
+func ordinalMatch(ordinal, n, days int) bool {
+	if ordinal > 0 {
+		return (n-1)/7+1 == ordinal
+	}
+	return -((days-n)/7 + 1) == ordinal
+}
+
+// matchSigned reports whether n, which is between 1 and length, is in list,
+// where negative entries count back from the end.
+func matchSigned(list []int, n, length int) bool {
+	for _, v := range list {
+		if v == n || v == n-length-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func matchInt(list []int, n int) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// setPos returns the entries of times, which is sorted, at the given
+// positions. Negative positions count back from the end.
+func setPos(times []civil.DateTime, positions []int) []civil.DateTime {
+	var indexes []int
+	for _, pos := range positions {
+		i := pos - 1
+		if pos < 0 {
+			i = len(times) + pos
+		}
+		if i >= 0 && i < len(times) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+	var result []civil.DateTime
+	for j, i := range indexes {
+		if j == 0 || indexes[j-1] != i {
+			result = append(result, times[i])
+		}
+	}
+	return result
+}
+
+// weekNumber returns the week-numbering year and week number of d, where
+// weeks start on wkst and week 1 is the first week with at least four days
+// in the year.
+func weekNumber(d civil.Date, wkst time.Weekday) (year, week int) {
+	year = d.Year()
+	start := firstWeekStart(year+1, wkst)
+	if !d.Before(start) {
+		year++
+	} else if start = firstWeekStart(year, wkst); d.Before(start) {
+		year--
+		start = firstWeekStart(year, wkst)
+	}
+	return year, daysBetween(start, d)/7 + 1
+}
+
+func weeksInYear(year int, wkst time.Weekday) int {
+	return daysBetween(firstWeekStart(year, wkst), firstWeekStart(year+1, wkst)) / 7
+}
+
+// firstWeekStart returns the first day of week 1 of year, which is
+// the week containing the 4th of January.
+func firstWeekStart(year int, wkst time.Weekday) civil.Date {
+	jan4 := civil.DateFor(year, time.January, 4)
+	return jan4.AddDate(0, 0, -daysSinceWeekStart(jan4, wkst))
+}
+
+func daysSinceWeekStart(d civil.Date, wkst time.Weekday) int {
+	return (int(d.Weekday()) - int(wkst) + 7) % 7
+}
+
+func daysBetween(a, b civil.Date) int {
+	return int(b.Sub(a) / (24 * time.Hour))
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return civil.DateFor(year, month+1, 0).Day()
+}
+
+func daysInYear(year int) int {
+	return civil.DateFor(year, time.December, 31).YearDay()
+}
+
+func clock(h, m, s int) time.Duration {
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+func truncate(dt civil.DateTime, d time.Duration) civil.DateTime {
+	midnight := midnight(dateOf(dt))
+	return midnight.Add(dt.Sub(midnight) / d * d)
+}
+
+func midnight(d civil.Date) civil.DateTime {
+	year, month, day := d.Date()
+	return civil.DateTimeFor(year, month, day, 0, 0, 0)
+}
+
+func dateOf(dt civil.DateTime) civil.Date {
+	year, month, day := dt.Date()
+	return civil.DateFor(year, month, day)
+}
+
+func sortedCopy(list []int) []int {
+	if len(list) == 0 {
+		return list
+	}
+	sorted := make([]int, len(list))
+	copy(sorted, list)
+	sort.Ints(sorted)
+	return sorted
+}
@@ -0,0 +1,421 @@
+// Package rrule expands iCalendar (RFC 5545) recurrence rules over civil
+// dates and date-times.
+//
+// A recurrence rule with a floating DTSTART, one without a time zone, describes
+// a series of civil date-times, and a rule with a DTSTART of VALUE=DATE describes
+// a series of civil dates. Expansion is done entirely in civil time, so the
+// results are the same regardless of time zone.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Frequency is the FREQ rule part, which identifies the type of recurrence.
+type Frequency int
+
+// Frequencies, from the longest period to the shortest.
+const (
+	Yearly Frequency = iota
+	Monthly
+	Weekly
+	Daily
+	Hourly
+	Minutely
+	Secondly
+)
+
+var frequencyNames = []string{
+	Yearly:   "YEARLY",
+	Monthly:  "MONTHLY",
+	Weekly:   "WEEKLY",
+	Daily:    "DAILY",
+	Hourly:   "HOURLY",
+	Minutely: "MINUTELY",
+	Secondly: "SECONDLY",
+}
+
+// String returns the RFC 5545 name of the frequency, such as "WEEKLY".
+func (f Frequency) String() string {
+	if f >= 0 && int(f) < len(frequencyNames) {
+		return frequencyNames[f]
+	}
+	return "Frequency(" + strconv.Itoa(int(f)) + ")"
+}
+
+var weekdayNames = []string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// WeekdayNum is an entry in the BYDAY rule part, such as "MO", "+1MO" or "-1FR".
+type WeekdayNum struct {
+	// N is the ordinal of the weekday within the month or year.
+	// If N is negative, it counts from the end of the month or year,
+	// so -1 means the last. If N is zero, every such weekday is included.
+	N       int
+	Weekday time.Weekday
+}
+
+// String returns the RFC 5545 representation of the weekday, such as "-1FR".
+func (wn WeekdayNum) String() string {
+	if wn.N == 0 {
+		return weekdayNames[wn.Weekday]
+	}
+	return strconv.Itoa(wn.N) + weekdayNames[wn.Weekday]
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd, name := range weekdayNames {
+		if s == name {
+			return time.Weekday(wd), true
+		}
+	}
+	return 0, false
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, bool) {
+	var wn WeekdayNum
+	if len(s) < 2 {
+		return wn, false
+	}
+	wd, ok := parseWeekday(s[len(s)-2:])
+	if !ok {
+		return wn, false
+	}
+	wn.Weekday = wd
+	if prefix := s[:len(s)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return wn, false
+		}
+		wn.N = n
+	}
+	return wn, true
+}
+
+// Rule is a recurrence rule, as specified by the RRULE property.
+type Rule struct {
+	// Start is the first occurrence, as specified by the DTSTART property.
+	// It also supplies the values of rule parts that are not specified,
+	// such as the time of day of a DAILY rule.
+	Start civil.DateTime
+
+	Freq Frequency
+
+	// Interval is the number of periods between occurrences, such as 2 for
+	// every second week. Zero is treated as 1.
+	Interval int
+
+	// Count is the number of occurrences, and Until is the last possible
+	// occurrence. At most one should be set. If neither is set, the rule
+	// recurs indefinitely. If the rule was parsed from an UNTIL value in UTC,
+	// such as "19971224T000000Z", Until is in UTC, and a Set with a Location
+	// converts it to that location before comparing it with occurrences.
+	Count int
+	Until civil.DateTime
+
+	BySecond   []int
+	ByMinute   []int
+	ByHour     []int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByYearDay  []int
+	ByWeekNo   []int
+	ByMonth    []int
+	BySetPos   []int
+
+	// WeekStart is the first day of the week, as specified by WKST.
+	// It affects WEEKLY rules with an interval greater than one and
+	// BYWEEKNO rules. ParseRule sets it to time.Monday unless WKST is
+	// specified, which is the RFC 5545 default.
+	WeekStart time.Weekday
+
+	// untilDate is set if UNTIL was a date rather than a date-time.
+	untilDate bool
+
+	// untilUTC is set if UNTIL was a date-time in UTC.
+	untilUTC bool
+}
+
+// ParseRule parses the value of an RRULE property, such as
+// "FREQ=MONTHLY;BYDAY=-1FR;COUNT=10". The "RRULE:" prefix is optional.
+// The rule starts at start.
+func ParseRule(s string, start civil.DateTime) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	r := &Rule{Start: start, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("rrule: invalid rule part %q", part)
+		}
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		if seen[name] {
+			return nil, fmt.Errorf("rrule: duplicate rule part %s", name)
+		}
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			err = errors.New("invalid frequency")
+			for f, fname := range frequencyNames {
+				if value == fname {
+					r.Freq, err = Frequency(f), nil
+				}
+			}
+		case "INTERVAL":
+			r.Interval, err = parseInt(value, 1, 1<<31-1)
+		case "COUNT":
+			r.Count, err = parseInt(value, 1, 1<<31-1)
+		case "UNTIL":
+			r.Until, r.untilDate, r.untilUTC, err = ParseValue(value)
+		case "BYSECOND":
+			r.BySecond, err = parseIntList(value, 0, 60, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseIntList(value, 0, 59, false)
+		case "BYHOUR":
+			r.ByHour, err = parseIntList(value, 0, 23, false)
+		case "BYDAY":
+			for _, item := range strings.Split(value, ",") {
+				wn, ok := parseWeekdayNum(item)
+				if !ok {
+					err = fmt.Errorf("invalid weekday %q", item)
+					break
+				}
+				r.ByDay = append(r.ByDay, wn)
+			}
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(value, -31, 31, true)
+		case "BYYEARDAY":
+			r.ByYearDay, err = parseIntList(value, -366, 366, true)
+		case "BYWEEKNO":
+			r.ByWeekNo, err = parseIntList(value, -53, 53, true)
+		case "BYMONTH":
+			r.ByMonth, err = parseIntList(value, 1, 12, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseIntList(value, -366, 366, true)
+		case "WKST":
+			var ok bool
+			if r.WeekStart, ok = parseWeekday(value); !ok {
+				err = errors.New("invalid weekday")
+			}
+		default:
+			// RFC 5545 allows extension rule parts (x-name), which are ignored
+			if !strings.HasPrefix(name, "X-") {
+				err = errors.New("unknown rule part")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rrule: %s: %v", name, err)
+		}
+	}
+	if !seen["FREQ"] {
+		return nil, errors.New("rrule: missing FREQ")
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate reports whether the rule breaks any of the constraints of RFC 5545.
+func (r *Rule) Validate() error {
+	if r.Freq < Yearly || r.Freq > Secondly {
+		return errors.New("rrule: invalid FREQ")
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return errors.New("rrule: COUNT and UNTIL must not both be specified")
+	}
+	for _, wn := range r.ByDay {
+		if wn.N == 0 {
+			continue
+		}
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return errors.New("rrule: BYDAY ordinals require a MONTHLY or YEARLY rule")
+		}
+		if r.Freq == Yearly && len(r.ByWeekNo) > 0 {
+			return errors.New("rrule: BYDAY ordinals are not allowed with BYWEEKNO")
+		}
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq == Weekly {
+		return errors.New("rrule: BYMONTHDAY is not allowed in a WEEKLY rule")
+	}
+	if len(r.ByYearDay) > 0 && (r.Freq == Daily || r.Freq == Weekly || r.Freq == Monthly) {
+		return errors.New("rrule: BYYEARDAY is not allowed in a " + r.Freq.String() + " rule")
+	}
+	if len(r.ByWeekNo) > 0 && r.Freq != Yearly {
+		return errors.New("rrule: BYWEEKNO requires a YEARLY rule")
+	}
+	if len(r.BySetPos) > 0 && len(r.BySecond)+len(r.ByMinute)+len(r.ByHour)+len(r.ByDay)+
+		len(r.ByMonthDay)+len(r.ByYearDay)+len(r.ByWeekNo)+len(r.ByMonth) == 0 {
+		return errors.New("rrule: BYSETPOS requires another BYxxx rule part")
+	}
+	return nil
+}
+
+// String returns the rule in the format of an RRULE value, without
+// the "RRULE:" prefix. The start of the rule is not included.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if !r.Until.IsZero() {
+		until := FormatValue(r.Until, r.untilDate)
+		if r.untilUTC && !r.untilDate {
+			until += "Z"
+		}
+		parts = append(parts, "UNTIL="+until)
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	appendInts := func(name string, values []int) {
+		if len(values) > 0 {
+			s := make([]string, len(values))
+			for i, v := range values {
+				s[i] = strconv.Itoa(v)
+			}
+			parts = append(parts, name+"="+strings.Join(s, ","))
+		}
+	}
+	appendInts("BYSECOND", r.BySecond)
+	appendInts("BYMINUTE", r.ByMinute)
+	appendInts("BYHOUR", r.ByHour)
+	if len(r.ByDay) > 0 {
+		s := make([]string, len(r.ByDay))
+		for i, wn := range r.ByDay {
+			s[i] = wn.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(s, ","))
+	}
+	appendInts("BYMONTHDAY", r.ByMonthDay)
+	appendInts("BYYEARDAY", r.ByYearDay)
+	appendInts("BYWEEKNO", r.ByWeekNo)
+	appendInts("BYMONTH", r.ByMonth)
+	appendInts("BYSETPOS", r.BySetPos)
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// Iterator returns an iterator over the occurrences of the rule.
+func (r *Rule) Iterator() *Iterator {
+	return &Iterator{source: newRuleIterator(r)}
+}
+
+// All returns all occurrences of the rule. It returns nil if the rule
+// has neither COUNT nor UNTIL, and so recurs indefinitely.
+func (r *Rule) All() []civil.DateTime {
+	if r.Count == 0 && r.Until.IsZero() {
+		return nil
+	}
+	return r.Iterator().all()
+}
+
+// Between returns the occurrences of the rule after a and before b.
+// If inclusive is true, occurrences equal to a or b are included.
+func (r *Rule) Between(a, b civil.DateTime, inclusive bool) []civil.DateTime {
+	return r.Iterator().between(a, b, inclusive)
+}
+
+// After returns the first occurrence of the rule after dt, or the zero
+// value and false if there is none. If inclusive is true, an occurrence
+// equal to dt is returned.
+func (r *Rule) After(dt civil.DateTime, inclusive bool) (civil.DateTime, bool) {
+	return r.Iterator().after(dt, inclusive)
+}
+
+// DatesBetween returns the dates of the occurrences of the rule
+// from a to b inclusive. It is intended for rules that start on a date
+// rather than a date-time.
+func (r *Rule) DatesBetween(a, b civil.Date) []civil.Date {
+	return r.Iterator().datesBetween(a, b)
+}
+
+func parseInt(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d out of range", n)
+	}
+	return n, nil
+}
+
+func parseIntList(s string, min, max int, nonZero bool) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(s, ",") {
+		n, err := parseInt(strings.TrimPrefix(item, "+"), min, max)
+		if err != nil {
+			return nil, err
+		}
+		if nonZero && n == 0 {
+			return nil, errors.New("0 out of range")
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// Layouts of iCalendar DATE and DATE-TIME values.
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+// ParseValue parses an iCalendar DATE value, such as "19970902", or a
+// DATE-TIME value, such as "19970902T090000". It reports whether the value
+// is a date, and whether it is a date-time in UTC, which has a trailing "Z",
+// such as "19970902T130000Z".
+func ParseValue(s string) (dt civil.DateTime, isDate bool, isUTC bool, err error) {
+	switch len(s) {
+	case len(dateLayout):
+		d, err := civil.ParseDateLayout(dateLayout, s)
+		if err != nil {
+			return dt, false, false, fmt.Errorf("invalid date %q", s)
+		}
+		year, month, day := d.Date()
+		return civil.DateTimeFor(year, month, day, 0, 0, 0), true, false, nil
+	case len(dateTimeLayout) + 1:
+		if s[len(s)-1] != 'Z' && s[len(s)-1] != 'z' {
+			break
+		}
+		dt, err = civil.ParseDateTimeLayout(dateTimeLayout, s[:len(s)-1])
+		if err == nil {
+			return dt, false, true, nil
+		}
+	case len(dateTimeLayout):
+		dt, err = civil.ParseDateTimeLayout(dateTimeLayout, s)
+		if err == nil {
+			return dt, false, false, nil
+		}
+	}
+	return civil.DateTime{}, false, false, fmt.Errorf("invalid date-time %q", s)
+}
+
+// FormatValue formats dt as an iCalendar DATE-TIME value, or as a DATE value
+// if isDate is true.
+func FormatValue(dt civil.DateTime, isDate bool) string {
+	if isDate {
+		return dt.Format(dateLayout)
+	}
+	return dt.Format(dateTimeLayout)
+}
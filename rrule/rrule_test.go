@@ -0,0 +1,473 @@
+package rrule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseValue(s string) civil.DateTime {
+	dt, _, _, err := ParseValue(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return dt
+}
+
+// expand returns a list of date-times from compact lists such as
+// "19970902T090000 19970903T090000" or, with a common time of day,
+// "T090000 19970902 19970903".
+func expand(s string) []string {
+	var list []string
+	var tod string
+	for _, field := range strings.Fields(s) {
+		if strings.HasPrefix(field, "T") {
+			tod = field
+			continue
+		}
+		list = append(list, mustParseValue(field+tod).String())
+	}
+	return list
+}
+
+func first(it *Iterator, n int) []string {
+	var list []string
+	for dt, ok := it.Next(); ok && len(list) < n; dt, ok = it.Next() {
+		list = append(list, dt.String())
+	}
+	return list
+}
+
+// TestRFC5545Examples checks the examples in section 3.8.5.3 of RFC 5545,
+// with floating date-times in place of those with time zones.
+func TestRFC5545Examples(t *testing.T) {
+	testCases := []struct {
+		Text     string
+		Expected string
+		All      bool // if false, only compare the first entries
+	}{
+		{ // daily for 10 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=DAILY;COUNT=10",
+			Expected: "T090000 19970902 19970903 19970904 19970905 19970906 19970907 19970908 19970909 19970910 19970911",
+			All:      true,
+		},
+		{ // every other day
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=DAILY;INTERVAL=2",
+			Expected: "T090000 19970902 19970904 19970906 19970908 19970910 19970912",
+		},
+		{ // every 10 days, 5 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=DAILY;INTERVAL=10;COUNT=5",
+			Expected: "T090000 19970902 19970912 19970922 19971002 19971012",
+			All:      true,
+		},
+		{ // weekly for 10 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;COUNT=10",
+			Expected: "T090000 19970902 19970909 19970916 19970923 19970930 19971007 19971014 19971021 19971028 19971104",
+			All:      true,
+		},
+		{ // weekly until December 24, 1997
+			Text: "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;UNTIL=19971224T000000Z",
+			Expected: "T090000 19970902 19970909 19970916 19970923 19970930 19971007 19971014 19971021 19971028" +
+				" 19971104 19971111 19971118 19971125 19971202 19971209 19971216 19971223",
+			All: true,
+		},
+		{ // every other week
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;WKST=SU",
+			Expected: "T090000 19970902 19970916 19970930 19971014 19971028 19971111 19971125 19971209 19971223 19980106 19980120",
+		},
+		{ // weekly on Tuesday and Thursday for five weeks
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			Expected: "T090000 19970902 19970904 19970909 19970911 19970916 19970918 19970923 19970925 19970930 19971002",
+			All:      true,
+		},
+		{
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;COUNT=10;WKST=SU;BYDAY=TU,TH",
+			Expected: "T090000 19970902 19970904 19970909 19970911 19970916 19970918 19970923 19970925 19970930 19971002",
+			All:      true,
+		},
+		{ // every other week on Monday, Wednesday and Friday until December 24, 1997
+			Text: "DTSTART:19970901T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR",
+			Expected: "T090000 19970901 19970903 19970905 19970915 19970917 19970919 19970929 19971001 19971003" +
+				" 19971013 19971015 19971017 19971027 19971029 19971031 19971110 19971112 19971114" +
+				" 19971124 19971126 19971128 19971208 19971210 19971212 19971222",
+			All: true,
+		},
+		{ // every other week on Tuesday and Thursday, for 8 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			Expected: "T090000 19970902 19970904 19970916 19970918 19970930 19971002 19971014 19971016",
+			All:      true,
+		},
+		{ // monthly on the first Friday for 10 occurrences
+			Text:     "DTSTART:19970905T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			Expected: "T090000 19970905 19971003 19971107 19971205 19980102 19980206 19980306 19980403 19980501 19980605",
+			All:      true,
+		},
+		{ // monthly on the first Friday until December 24, 1997
+			Text:     "DTSTART:19970905T090000\nRRULE:FREQ=MONTHLY;UNTIL=19971224T000000Z;BYDAY=1FR",
+			Expected: "T090000 19970905 19971003 19971107 19971205",
+			All:      true,
+		},
+		{ // every other month on the first and last Sunday for 10 occurrences
+			Text:     "DTSTART:19970907T090000\nRRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			Expected: "T090000 19970907 19970928 19971102 19971130 19980104 19980125 19980301 19980329 19980503 19980531",
+			All:      true,
+		},
+		{ // monthly on the second-to-last Monday for 6 months
+			Text:     "DTSTART:19970922T090000\nRRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			Expected: "T090000 19970922 19971020 19971117 19971222 19980119 19980216",
+			All:      true,
+		},
+		{ // monthly on the third-to-the-last day of the month
+			Text:     "DTSTART:19970928T090000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-3",
+			Expected: "T090000 19970928 19971029 19971128 19971229 19980129 19980226",
+		},
+		{ // monthly on the 2nd and 15th of the month for 10 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			Expected: "T090000 19970902 19970915 19971002 19971015 19971102 19971115 19971202 19971215 19980102 19980115",
+			All:      true,
+		},
+		{ // monthly on the first and last day of the month for 10 occurrences
+			Text:     "DTSTART:19970930T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYMONTHDAY=1,-1",
+			Expected: "T090000 19970930 19971001 19971031 19971101 19971130 19971201 19971231 19980101 19980131 19980201",
+			All:      true,
+		},
+		{ // every 18 months on the 10th thru 15th of the month for 10 occurrences
+			Text:     "DTSTART:19970910T090000\nRRULE:FREQ=MONTHLY;INTERVAL=18;COUNT=10;BYMONTHDAY=10,11,12,13,14,15",
+			Expected: "T090000 19970910 19970911 19970912 19970913 19970914 19970915 19990310 19990311 19990312 19990313",
+			All:      true,
+		},
+		{ // every Tuesday, every other month
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=TU",
+			Expected: "T090000 19970902 19970909 19970916 19970923 19970930 19971104 19971111 19971118 19971125 19980106 19980113",
+		},
+		{ // yearly in June and July for 10 occurrences
+			Text:     "DTSTART:19970610T090000\nRRULE:FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			Expected: "T090000 19970610 19970710 19980610 19980710 19990610 19990710 20000610 20000710 20010610 20010710",
+			All:      true,
+		},
+		{ // every other year on January, February, and March for 10 occurrences
+			Text:     "DTSTART:19970310T090000\nRRULE:FREQ=YEARLY;INTERVAL=2;COUNT=10;BYMONTH=1,2,3",
+			Expected: "T090000 19970310 19990110 19990210 19990310 20010110 20010210 20010310 20030110 20030210 20030310",
+			All:      true,
+		},
+		{ // every third year on the 1st, 100th, and 200th day for 10 occurrences
+			Text:     "DTSTART:19970101T090000\nRRULE:FREQ=YEARLY;INTERVAL=3;COUNT=10;BYYEARDAY=1,100,200",
+			Expected: "T090000 19970101 19970410 19970719 20000101 20000409 20000718 20030101 20030410 20030719 20060101",
+			All:      true,
+		},
+		{ // every 20th Monday of the year
+			Text:     "DTSTART:19970519T090000\nRRULE:FREQ=YEARLY;BYDAY=20MO",
+			Expected: "T090000 19970519 19980518 19990517",
+		},
+		{ // Monday of week number 20
+			Text:     "DTSTART:19970512T090000\nRRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO",
+			Expected: "T090000 19970512 19980511 19990517",
+		},
+		{ // every Thursday in March
+			Text:     "DTSTART:19970313T090000\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			Expected: "T090000 19970313 19970320 19970327 19980305 19980312 19980319 19980326 19990304 19990311 19990318 19990325",
+		},
+		{ // every Thursday, but only during June, July, and August
+			Text: "DTSTART:19970605T090000\nRRULE:FREQ=YEARLY;BYDAY=TH;BYMONTH=6,7,8",
+			Expected: "T090000 19970605 19970612 19970619 19970626 19970703 19970710 19970717 19970724 19970731" +
+				" 19970807 19970814 19970821 19970828 19980604 19980611 19980618 19980625 19980702 19980709" +
+				" 19980716 19980723 19980730 19980806 19980813 19980820 19980827",
+		},
+		{ // every Friday the 13th, excluding DTSTART
+			Text:     "DTSTART:19970902T090000\nEXDATE:19970902T090000\nRRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			Expected: "T090000 19980213 19980313 19981113 19990813 20001013",
+		},
+		{ // the first Saturday that follows the first Sunday of the month
+			Text:     "DTSTART:19970913T090000\nRRULE:FREQ=MONTHLY;BYDAY=SA;BYMONTHDAY=7,8,9,10,11,12,13",
+			Expected: "T090000 19970913 19971011 19971108 19971213 19980110 19980207 19980307 19980411 19980509 19980613",
+		},
+		{ // US Presidential Election day
+			Text:     "DTSTART:19961105T090000\nRRULE:FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8",
+			Expected: "T090000 19961105 20001107 20041102",
+		},
+		{ // the third instance of a Tuesday, Wednesday, or Thursday, for the next 3 months
+			Text:     "DTSTART:19970904T090000\nRRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+			Expected: "T090000 19970904 19971007 19971106",
+			All:      true,
+		},
+		{ // the second-to-last weekday of the month
+			Text:     "DTSTART:19970929T090000\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2",
+			Expected: "T090000 19970929 19971030 19971127 19971230 19980129 19980226 19980330",
+		},
+		{ // every 3 hours from 9:00 AM to 5:00 PM on a specific day
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T170000Z",
+			Expected: "19970902T090000 19970902T120000 19970902T150000",
+			All:      true,
+		},
+		{ // every 15 minutes for 6 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=MINUTELY;INTERVAL=15;COUNT=6",
+			Expected: "19970902T090000 19970902T091500 19970902T093000 19970902T094500 19970902T100000 19970902T101500",
+			All:      true,
+		},
+		{ // every hour and a half for 4 occurrences
+			Text:     "DTSTART:19970902T090000\nRRULE:FREQ=MINUTELY;INTERVAL=90;COUNT=4",
+			Expected: "19970902T090000 19970902T103000 19970902T120000 19970902T133000",
+			All:      true,
+		},
+		{ // an example where the days generated makes a difference because of WKST
+			Text:     "DTSTART:19970805T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			Expected: "T090000 19970805 19970810 19970819 19970824",
+			All:      true,
+		},
+		{
+			Text:     "DTSTART:19970805T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			Expected: "T090000 19970805 19970817 19970819 19970831",
+			All:      true,
+		},
+		{ // an invalid date (February 30) is ignored
+			Text:     "DTSTART:20070115T090000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=15,30;COUNT=5",
+			Expected: "T090000 20070115 20070130 20070215 20070315 20070330",
+			All:      true,
+		},
+	}
+	for i, tc := range testCases {
+		set, err := Parse(tc.Text)
+		if !assert.NoError(t, err, "%d: %s", i, tc.Text) {
+			continue
+		}
+		expected := expand(tc.Expected)
+		if tc.All {
+			var actual []string
+			for _, dt := range set.All() {
+				actual = append(actual, dt.String())
+			}
+			assert.Equal(t, expected, actual, "%d: %s", i, tc.Text)
+		} else {
+			assert.Equal(t, expected, first(set.Iterator(), len(expected)), "%d: %s", i, tc.Text)
+		}
+	}
+}
+
+func TestEveryDayInJanuary(t *testing.T) {
+	rules := []string{
+		"FREQ=YEARLY;UNTIL=20000131T140000Z;BYMONTH=1;BYDAY=SU,MO,TU,WE,TH,FR,SA",
+		"FREQ=DAILY;UNTIL=20000131T140000Z;BYMONTH=1",
+	}
+	for _, s := range rules {
+		rule, err := ParseRule(s, mustParseValue("19980101T090000"))
+		assert.NoError(t, err)
+		all := rule.All()
+		assert.Len(t, all, 93, s)
+		assert.Equal(t, "1998-01-01T09:00:00", all[0].String())
+		assert.Equal(t, "1999-01-01T09:00:00", all[31].String())
+		assert.Equal(t, "2000-01-31T09:00:00", all[92].String())
+	}
+}
+
+func TestEveryTwentyMinutes(t *testing.T) {
+	start := mustParseValue("19970902T090000")
+	daily, err := ParseRule("FREQ=DAILY;BYHOUR=9,10,11,12,13,14,15,16;BYMINUTE=0,20,40", start)
+	assert.NoError(t, err)
+	minutely, err := ParseRule("FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16", start)
+	assert.NoError(t, err)
+
+	end := mustParseValue("19970904T000000")
+	expected := daily.Between(start, end, true)
+	assert.Len(t, expected, 48)
+	assert.Equal(t, "1997-09-02T16:40:00", expected[23].String())
+	assert.Equal(t, "1997-09-03T09:00:00", expected[24].String())
+	assert.Equal(t, expected, minutely.Between(start, end, true))
+}
+
+func TestDailyUntil(t *testing.T) {
+	rule, err := ParseRule("RRULE:FREQ=DAILY;UNTIL=19971224T000000Z", mustParseValue("19970902T090000"))
+	assert.NoError(t, err)
+	all := rule.All()
+	assert.Len(t, all, 113)
+	assert.Equal(t, "1997-12-23T09:00:00", all[112].String())
+}
+
+// TestUntilUTC checks that UNTIL and EXDATE values in UTC are compared as
+// instants when DTSTART has a time zone, as required by RFC 5545.
+func TestUntilUTC(t *testing.T) {
+	assert := assert.New(t)
+
+	// daily until December 24, 1997, from section 3.8.5.3 of RFC 5545
+	set, err := Parse("DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;UNTIL=19971224T000000Z")
+	assert.NoError(err)
+	all := set.All()
+	assert.Len(all, 113)
+	assert.Equal("1997-12-23T09:00:00", all[112].String())
+
+	// 1997-12-23T20:00 in New York is 1997-12-24T01:00Z, which is after UNTIL
+	text := "DTSTART;TZID=America/New_York:19971220T200000\r\n" +
+		"RRULE:FREQ=DAILY;UNTIL=19971224T000000Z"
+	set, err = Parse(text)
+	assert.NoError(err)
+	assert.Equal(expand("T200000 19971220 19971221 19971222"), toStrings(set.All()))
+	assert.Equal(text, set.String())
+
+	// 1997-12-22T01:00Z is 1997-12-21T20:00 in New York
+	set, err = Parse(text + "\nEXDATE:19971222T010000Z")
+	assert.NoError(err)
+	assert.Equal(expand("T200000 19971220 19971222"), toStrings(set.All()))
+	assert.Equal(expand("T200000 19971221"), toStrings(set.ExDates))
+
+	// a UTC DTSTART is not floating
+	text = "DTSTART:19971220T200000Z\r\nRRULE:FREQ=DAILY;UNTIL=19971222T200000Z"
+	set, err = Parse(text)
+	assert.NoError(err)
+	assert.Equal(time.UTC, set.Location)
+	assert.Equal(expand("T200000 19971220 19971221 19971222"), toStrings(set.All()))
+	assert.Equal(text, set.String())
+}
+
+func TestRuleQueries(t *testing.T) {
+	assert := assert.New(t)
+	rule, err := ParseRule("FREQ=WEEKLY;BYDAY=MO,WE", mustParseValue("20210301T083000"))
+	assert.NoError(err)
+	assert.Nil(rule.All())
+
+	between := rule.Between(mustParseValue("20210303T083000"), mustParseValue("20210310T083000"), false)
+	assert.Equal(expand("T083000 20210308"), toStrings(between))
+	between = rule.Between(mustParseValue("20210303T083000"), mustParseValue("20210310T083000"), true)
+	assert.Equal(expand("T083000 20210303 20210308 20210310"), toStrings(between))
+
+	dt, ok := rule.After(mustParseValue("20210308T083000"), false)
+	assert.True(ok)
+	assert.Equal("2021-03-10T08:30:00", dt.String())
+	dt, ok = rule.After(mustParseValue("20210308T083000"), true)
+	assert.True(ok)
+	assert.Equal("2021-03-08T08:30:00", dt.String())
+
+	rule.Count = 2
+	_, ok = rule.After(mustParseValue("20210303T083000"), false)
+	assert.False(ok)
+
+	// the 30th of February never occurs
+	rule, err = ParseRule("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", mustParseValue("20210101T000000"))
+	assert.NoError(err)
+	_, ok = rule.After(rule.Start, true)
+	assert.False(ok)
+}
+
+func TestRuleDates(t *testing.T) {
+	set, err := Parse("DTSTART;VALUE=DATE:20210101\nRRULE:FREQ=MONTHLY;BYDAY=-1FR\nEXDATE;VALUE=DATE:20210430")
+	assert.NoError(t, err)
+	assert.True(t, set.DateOnly)
+	var dates []string
+	for _, d := range set.DatesBetween(civil.DateFor(2021, 1, 1), civil.DateFor(2021, 6, 30)) {
+		dates = append(dates, d.String())
+	}
+	assert.Equal(t, []string{"2021-01-01", "2021-01-29", "2021-02-26", "2021-03-26", "2021-05-28", "2021-06-25"}, dates)
+}
+
+func TestSet(t *testing.T) {
+	assert := assert.New(t)
+	text := "DTSTART;TZID=Australia/Sydney:20210301T090000\r\n" +
+		"RRULE:FREQ=DAILY;COUNT=3\r\n" +
+		"RRULE:FREQ=WEEKLY;COUNT=2;INTERVAL=2\r\n" +
+		"RDATE;TZID=Australia/Sydney:20210310T090000,20210302T090000\r\n" +
+		"EXDATE;TZID=Australia/Sydney:20210303T090000"
+	set, err := Parse(text)
+	assert.NoError(err)
+	assert.Equal("Australia/Sydney", set.Location.String())
+	assert.Equal(expand("T090000 20210301 20210302 20210310 20210315"), toStrings(set.All()))
+	assert.Equal(text, set.String())
+
+	for _, s := range []string{
+		"RRULE:FREQ=DAILY",
+		"DTSTART:20210301T090000\nDTSTART:20210301T090000",
+		"DTSTART:20210301T090000\nRRULE:FREQ=FORTNIGHTLY",
+		"DTSTART:20210301T090000\nEXRULE:FREQ=DAILY",
+		"DTSTART;VALUE=DATE:20210301T090000",
+		"DTSTART;TZID=Nowhere/Special:20210301T090000",
+		"DTSTART:20210301T090000\nRDATE;VALUE=PERIOD:19960403T020000Z/19960403T040000Z",
+		"no colon",
+	} {
+		_, err := Parse(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	start := mustParseValue("20210301T090000")
+	valid := []string{
+		"FREQ=DAILY",
+		"FREQ=MONTHLY;UNTIL=20211231;INTERVAL=2;BYDAY=1MO,-1FR",
+		"FREQ=YEARLY;COUNT=5;BYMONTHDAY=-1;BYMONTH=2",
+		"FREQ=YEARLY;BYDAY=MO;BYWEEKNO=1,-1;WKST=SU",
+		"FREQ=HOURLY;BYSECOND=0,30;BYMINUTE=15;BYHOUR=9",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1,-1",
+		"FREQ=YEARLY;BYYEARDAY=1,-1",
+	}
+	for _, s := range valid {
+		rule, err := ParseRule(s, start)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, s, rule.String())
+			assert.Equal(t, start, rule.Start)
+		}
+	}
+
+	// extension parts are ignored, and names are not case sensitive
+	rule, err := ParseRule("freq=weekly;x-name=value;byday=tu", start)
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=TU", rule.String())
+	assert.Equal(t, time.Monday, rule.WeekStart)
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;COUNT=2;UNTIL=20211231",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;UNTIL=2021-12-31",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYMONTHDAY=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=YEARLY;BYWEEKNO=1;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYYEARDAY=1",
+		"FREQ=MONTHLY;BYWEEKNO=1",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=DAILY;WKST=XX",
+		"FREQ=DAILY;UNKNOWN=1",
+		"FREQ=DAILY;BYDAY",
+	}
+	for _, s := range invalid {
+		_, err := ParseRule(s, start)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestWeekNumber(t *testing.T) {
+	testCases := []struct {
+		Date string
+		WKST time.Weekday
+		Year int
+		Week int
+	}{
+		{"1997-12-29", time.Monday, 1998, 1},
+		{"1998-01-04", time.Monday, 1998, 1},
+		{"1998-01-04", time.Sunday, 1998, 1},
+		{"1998-01-03", time.Sunday, 1997, 53},
+		{"2021-01-03", time.Monday, 2020, 53},
+		{"2021-01-04", time.Monday, 2021, 1},
+	}
+	for _, tc := range testCases {
+		d, _ := civil.ParseDate(tc.Date)
+		year, week := weekNumber(d, tc.WKST)
+		assert.Equal(t, tc.Year, year, tc.Date)
+		assert.Equal(t, tc.Week, week, tc.Date)
+	}
+	assert.Equal(t, 53, weeksInYear(2020, time.Monday))
+	assert.Equal(t, 52, weeksInYear(2021, time.Monday))
+}
+
+func toStrings(list []civil.DateTime) []string {
+	var s []string
+	for _, dt := range list {
+		s = append(s, dt.String())
+	}
+	return s
+}
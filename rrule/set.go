@@ -0,0 +1,325 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+// Set is a recurrence set: the start, the occurrences of the rules and the
+// additional dates of RDATE, less the dates of EXDATE.
+type Set struct {
+	// Start is the first occurrence, as specified by DTSTART.
+	Start civil.DateTime
+
+	// DateOnly is true if DTSTART is a date (VALUE=DATE) rather than
+	// a date-time. Occurrences are then at midnight.
+	DateOnly bool
+
+	// Location is the time zone specified by the TZID parameter of
+	// DTSTART, time.UTC if DTSTART is in UTC, or nil if DTSTART is
+	// floating. Occurrences are always civil date-times, which can be
+	// converted to instants in Location.
+	Location *time.Location
+
+	// Rules, RDates and ExDates are in Location. Parse converts RDATE and
+	// EXDATE values in other time zones, such as UTC, to Location.
+	Rules   []*Rule
+	RDates  []civil.DateTime
+	ExDates []civil.DateTime
+}
+
+// Parse parses a recurrence set from lines of DTSTART, RRULE, RDATE and EXDATE
+// properties, such as:
+//
+//	DTSTART:19970902T090000
+//	RRULE:FREQ=WEEKLY;COUNT=10
+//	EXDATE:19970909T090000
+//
+// Lines must already be unfolded. DTSTART is required.
+func Parse(s string) (*Set, error) {
+	set := &Set{}
+	var rules []string
+	var rdates, exdates []dateValue
+	hasStart := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			return nil, fmt.Errorf("rrule: invalid line %q", line)
+		}
+		params := strings.Split(line[:colon], ";")
+		name, value := strings.ToUpper(params[0]), line[colon+1:]
+		params = params[1:]
+		switch name {
+		case "DTSTART":
+			if hasStart {
+				return nil, errors.New("rrule: duplicate DTSTART")
+			}
+			hasStart = true
+			values, isDate, err := parseDateList(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("rrule: DTSTART: %v", err)
+			}
+			if len(values) != 1 {
+				return nil, errors.New("rrule: DTSTART must have one value")
+			}
+			set.Start, set.DateOnly, set.Location = values[0].dt, isDate, values[0].loc
+		case "RRULE":
+			rules = append(rules, value)
+		case "RDATE", "EXDATE":
+			values, _, err := parseDateList(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("rrule: %s: %v", name, err)
+			}
+			if name == "RDATE" {
+				rdates = append(rdates, values...)
+			} else {
+				exdates = append(exdates, values...)
+			}
+		default:
+			return nil, fmt.Errorf("rrule: unsupported property %s", name)
+		}
+	}
+	if !hasStart {
+		return nil, errors.New("rrule: missing DTSTART")
+	}
+	for _, s := range rules {
+		rule, err := ParseRule(s, set.Start)
+		if err != nil {
+			return nil, err
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+	set.RDates = set.localize(rdates)
+	set.ExDates = set.localize(exdates)
+	return set, nil
+}
+
+// dateValue is a DATE or DATE-TIME value and its time zone, which is
+// nil for a floating value.
+type dateValue struct {
+	dt  civil.DateTime
+	loc *time.Location
+}
+
+// parseDateList parses a comma-separated list of DATE or DATE-TIME values
+// with the property parameters VALUE and TZID.
+func parseDateList(s string, params []string) (values []dateValue, isDate bool, err error) {
+	var loc *time.Location
+	for _, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return nil, false, fmt.Errorf("invalid parameter %q", param)
+		}
+		switch strings.ToUpper(kv[0]) {
+		case "VALUE":
+			switch v := strings.ToUpper(kv[1]); v {
+			case "DATE":
+				isDate = true
+			case "DATE-TIME":
+			default:
+				return nil, false, fmt.Errorf("unsupported value type %s", v)
+			}
+		case "TZID":
+			if loc, err = civil.LoadLocation(strings.Trim(kv[1], `"`)); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	for _, item := range strings.Split(s, ",") {
+		dt, date, utc, err := ParseValue(strings.TrimSpace(item))
+		if err != nil {
+			return nil, false, err
+		}
+		if date != isDate {
+			return nil, false, fmt.Errorf("value %q does not match the value type", item)
+		}
+		v := dateValue{dt: dt, loc: loc}
+		if utc {
+			v.loc = time.UTC
+		}
+		values = append(values, v)
+	}
+	return values, isDate, nil
+}
+
+// localize returns the values as civil date-times in the location of the set.
+// Values in another time zone are converted to the location of the set,
+// so that they can be compared with occurrences.
+func (set *Set) localize(values []dateValue) []civil.DateTime {
+	var list []civil.DateTime
+	for _, v := range values {
+		dt := v.dt
+		if v.loc != nil && set.Location != nil && !set.DateOnly && v.loc.String() != set.Location.String() {
+			dt = convert(dt, v.loc, set.Location)
+		}
+		list = append(list, dt)
+	}
+	return list
+}
+
+// convert returns the civil date-time in to of the instant dt in from.
+func convert(dt civil.DateTime, from, to *time.Location) civil.DateTime {
+	// the compatible disambiguation never returns an error
+	t, _ := dt.In(from, civil.DisambiguateCompatible)
+	return civil.DateTimeOf(t.In(to))
+}
+
+// String returns the set as lines of DTSTART, RRULE, RDATE and EXDATE
+// properties, separated by CRLF.
+func (set *Set) String() string {
+	params, suffix := "", ""
+	switch {
+	case set.DateOnly:
+		params += ";VALUE=DATE"
+	case set.Location == time.UTC:
+		suffix = "Z"
+	case set.Location != nil:
+		params += ";TZID=" + set.Location.String()
+	}
+	format := func(name string, values []civil.DateTime) string {
+		s := make([]string, len(values))
+		for i, dt := range values {
+			s[i] = FormatValue(dt, set.DateOnly) + suffix
+		}
+		return name + params + ":" + strings.Join(s, ",")
+	}
+	lines := []string{format("DTSTART", []civil.DateTime{set.Start})}
+	for _, rule := range set.Rules {
+		lines = append(lines, "RRULE:"+rule.String())
+	}
+	if len(set.RDates) > 0 {
+		lines = append(lines, format("RDATE", set.RDates))
+	}
+	if len(set.ExDates) > 0 {
+		lines = append(lines, format("EXDATE", set.ExDates))
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+// Iterator returns an iterator over the occurrences of the set.
+func (set *Set) Iterator() *Iterator {
+	it := &setIterator{
+		rdates:  sortedDateTimes(append([]civil.DateTime{set.Start}, set.RDates...)),
+		exdates: make(map[int64]bool, len(set.ExDates)),
+	}
+	for _, rule := range set.Rules {
+		r := *rule
+		if r.Start.IsZero() {
+			r.Start = set.Start
+		}
+		if r.untilUTC && set.Location != nil && !r.Until.IsZero() {
+			r.Until = convert(r.Until, time.UTC, set.Location)
+		}
+		it.rules = append(it.rules, &peekIterator{source: newRuleIterator(&r)})
+	}
+	for _, dt := range set.ExDates {
+		it.exdates[dt.Unix()] = true
+	}
+	return &Iterator{source: it}
+}
+
+// All returns all occurrences of the set. It returns nil if any of the rules
+// recurs indefinitely.
+func (set *Set) All() []civil.DateTime {
+	for _, rule := range set.Rules {
+		if rule.Count == 0 && rule.Until.IsZero() {
+			return nil
+		}
+	}
+	return set.Iterator().all()
+}
+
+// Between returns the occurrences of the set after a and before b.
+// If inclusive is true, occurrences equal to a or b are included.
+func (set *Set) Between(a, b civil.DateTime, inclusive bool) []civil.DateTime {
+	return set.Iterator().between(a, b, inclusive)
+}
+
+// After returns the first occurrence of the set after dt, or the zero
+// value and false if there is none. If inclusive is true, an occurrence
+// equal to dt is returned.
+func (set *Set) After(dt civil.DateTime, inclusive bool) (civil.DateTime, bool) {
+	return set.Iterator().after(dt, inclusive)
+}
+
+// DatesBetween returns the dates of the occurrences of the set
+// from a to b inclusive.
+func (set *Set) DatesBetween(a, b civil.Date) []civil.Date {
+	return set.Iterator().datesBetween(a, b)
+}
+
+// setIterator merges the occurrences of the rules and the additional dates,
+// removing duplicates and excluded dates.
+type setIterator struct {
+	rules   []*peekIterator
+	rdates  []civil.DateTime
+	exdates map[int64]bool
+	last    civil.DateTime
+	started bool
+}
+
+func (it *setIterator) next() (civil.DateTime, bool) {
+	for {
+		var next civil.DateTime
+		var source *peekIterator
+		found := len(it.rdates) > 0
+		if found {
+			next = it.rdates[0]
+		}
+		for _, rule := range it.rules {
+			if dt, ok := rule.peek(); ok && (!found || dt.Before(next)) {
+				next, source, found = dt, rule, true
+			}
+		}
+		if !found {
+			return civil.DateTime{}, false
+		}
+		if source != nil {
+			source.pop()
+		} else {
+			it.rdates = it.rdates[1:]
+		}
+		if (it.started && next.Equal(it.last)) || it.exdates[next.Unix()] {
+			continue
+		}
+		it.last, it.started = next, true
+		return next, true
+	}
+}
+
+// peekIterator allows the next occurrence of a rule to be examined
+// without consuming it.
+type peekIterator struct {
+	source *ruleIterator
+	head   civil.DateTime
+	ok     bool
+	peeked bool
+}
+
+func (it *peekIterator) peek() (civil.DateTime, bool) {
+	if !it.peeked {
+		it.head, it.ok = it.source.next()
+		it.peeked = true
+	}
+	return it.head, it.ok
+}
+
+func (it *peekIterator) pop() {
+	it.peeked = false
+}
+
+func sortedDateTimes(list []civil.DateTime) []civil.DateTime {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Before(list[j])
+	})
+	return list
+}
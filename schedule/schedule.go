@@ -0,0 +1,296 @@
+// Package schedule generates the dates of financial schedules, such as the
+// coupon dates of a bond or the payment dates of a swap.
+//
+// Dates are first generated without adjustment, using a frequency, a roll
+// convention and a stub convention. Each date is then adjusted to a business
+// day using a civil.BusinessCalendar and a civil.BusinessDayConvention.
+// The conventions follow the ISDA definitions.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jjeffery/civil"
+)
+
+var (
+	errEndBeforeStart   = errors.New("schedule: end date is not after start date")
+	errNegativeFreq     = errors.New("schedule: frequency is negative")
+	errInvalidRoll      = errors.New("schedule: roll is not a day of the month, EOM or IMM")
+	errRegularOutOfSpan = errors.New("schedule: regular dates are not in order between the start and end dates")
+	errNotRegular       = errors.New("schedule: last regular date is not a whole number of periods after first regular date")
+)
+
+// Roll is the day of the month on which regular dates fall.
+// A Roll from 1 to 31 is that day of the month, or the last day of
+// a month that has fewer days. Other values, apart from the constants
+// below, are not valid.
+type Roll int
+
+const (
+	// RollNone rolls on the day of the month of the date that regular dates
+	// are generated from, or the last day of a month that has fewer days.
+	RollNone Roll = 0
+
+	// RollEOM rolls on the last day of the month.
+	RollEOM Roll = -1
+
+	// RollIMM rolls on the third Wednesday of the month, which is the
+	// settlement date of International Money Market futures.
+	RollIMM Roll = -2
+)
+
+// String returns "none", "EOM", "IMM", or the day of the month.
+func (r Roll) String() string {
+	switch r {
+	case RollNone:
+		return "none"
+	case RollEOM:
+		return "EOM"
+	case RollIMM:
+		return "IMM"
+	}
+	if !r.valid() {
+		return fmt.Sprintf("Roll(%d)", int(r))
+	}
+	return strconv.Itoa(int(r))
+}
+
+func (r Roll) valid() bool {
+	return r >= RollIMM && r <= 31
+}
+
+// Stub determines where a schedule has an irregular period when the start
+// and end dates are not a whole number of periods apart.
+type Stub int
+
+const (
+	// ShortInitial generates dates back from the end date, with a short
+	// first period.
+	ShortInitial Stub = iota
+
+	// LongInitial generates dates back from the end date, with a long
+	// first period.
+	LongInitial
+
+	// ShortFinal generates dates forward from the start date, with a short
+	// last period.
+	ShortFinal
+
+	// LongFinal generates dates forward from the start date, with a long
+	// last period.
+	LongFinal
+)
+
+var stubNames = []string{
+	ShortInitial: "short-initial",
+	LongInitial:  "long-initial",
+	ShortFinal:   "short-final",
+	LongFinal:    "long-final",
+}
+
+// String returns the name of the stub, such as "short-initial".
+func (s Stub) String() string {
+	if s >= 0 && int(s) < len(stubNames) {
+		return stubNames[s]
+	}
+	return fmt.Sprintf("Stub(%d)", int(s))
+}
+
+// Schedule describes a schedule of dates.
+type Schedule struct {
+	// Start is the effective date, which is the start of the first period.
+	Start civil.Date
+
+	// End is the termination date, which is the end of the last period.
+	End civil.Date
+
+	// Frequency is the length of a regular period, such as civil.PeriodOf(0, 3, 0)
+	// for quarterly. A zero frequency has one period from Start to End.
+	Frequency civil.Period
+
+	// Roll is the roll convention for regular dates. It applies only to
+	// frequencies of whole months and years.
+	Roll Roll
+
+	// Stub is the stub convention. If FirstRegular is set, dates are generated
+	// forward from it and only LongFinal has an effect. If LastRegular is set,
+	// dates are generated back from it and only LongInitial has an effect.
+	Stub Stub
+
+	// FirstRegular, if not zero, is the start of the first regular period.
+	// There is an initial stub from Start to FirstRegular.
+	FirstRegular civil.Date
+
+	// LastRegular, if not zero, is the end of the last regular period.
+	// There is a final stub from LastRegular to End. If both FirstRegular
+	// and LastRegular are set, they must be a whole number of periods apart.
+	LastRegular civil.Date
+
+	// Calendar determines business days. If nil, dates are not adjusted.
+	Calendar *civil.BusinessCalendar
+
+	// Convention is used to adjust every date in the schedule, including
+	// Start and End, to a business day.
+	Convention civil.BusinessDayConvention
+}
+
+// Unadjusted returns the dates of the schedule before adjustment, starting
+// with Start and ending with End.
+func (s Schedule) Unadjusted() ([]civil.Date, error) {
+	if !s.End.After(s.Start) {
+		return nil, errEndBeforeStart
+	}
+	if !s.Roll.valid() {
+		return nil, errInvalidRoll
+	}
+	freq := s.Frequency
+	if freq.Years < 0 || freq.Months < 0 || freq.Days < 0 {
+		return nil, errNegativeFreq
+	}
+	if freq.IsZero() {
+		return []civil.Date{s.Start, s.End}, nil
+	}
+	first, last := s.Start, s.End
+	if !s.FirstRegular.IsZero() {
+		first = s.FirstRegular
+	}
+	if !s.LastRegular.IsZero() {
+		last = s.LastRegular
+	}
+	if first.Before(s.Start) || last.After(s.End) || !last.After(first) {
+		return nil, errRegularOutOfSpan
+	}
+
+	switch {
+	case !s.FirstRegular.IsZero() && !s.LastRegular.IsZero():
+		dates, stub := s.forward(s.FirstRegular, s.LastRegular)
+		if stub {
+			return nil, errNotRegular
+		}
+		return s.withEnds(dates), nil
+	case !s.FirstRegular.IsZero():
+		dates, stub := s.forward(s.FirstRegular, s.End)
+		return s.withEnds(lengthen(dates, stub && s.Stub == LongFinal, false)), nil
+	case !s.LastRegular.IsZero():
+		dates, stub := s.backward(s.Start, s.LastRegular)
+		return s.withEnds(lengthen(dates, stub && s.Stub == LongInitial, true)), nil
+	case s.Stub == ShortFinal || s.Stub == LongFinal:
+		dates, stub := s.forward(s.Start, s.End)
+		return lengthen(dates, stub && s.Stub == LongFinal, false), nil
+	default:
+		dates, stub := s.backward(s.Start, s.End)
+		return lengthen(dates, stub && s.Stub == LongInitial, true), nil
+	}
+}
+
+// Adjusted returns the dates of the schedule adjusted to business days.
+func (s Schedule) Adjusted() ([]civil.Date, error) {
+	dates, err := s.Unadjusted()
+	if err != nil {
+		return nil, err
+	}
+	if s.Calendar == nil {
+		return dates, nil
+	}
+	adjusted := make([]civil.Date, len(dates))
+	for i, d := range dates {
+		adjusted[i] = s.Calendar.Adjust(d, s.Convention)
+	}
+	return adjusted, nil
+}
+
+// forward returns the dates generated forward from start, ending with end.
+// It reports whether the last period is a stub.
+func (s Schedule) forward(start, end civil.Date) ([]civil.Date, bool) {
+	dates := []civil.Date{start}
+	for n := 1; ; n++ {
+		d := s.generate(start, n)
+		if !d.Before(end) {
+			return append(dates, end), !d.Equal(end)
+		}
+		dates = append(dates, d)
+	}
+}
+
+// backward returns the dates generated back from end, starting with start.
+// It reports whether the first period is a stub.
+func (s Schedule) backward(start, end civil.Date) ([]civil.Date, bool) {
+	dates := []civil.Date{end}
+	for n := -1; ; n-- {
+		d := s.generate(end, n)
+		if !d.After(start) {
+			dates = append(dates, start)
+			for i, j := 0, len(dates)-1; i < j; i, j = i+1, j-1 {
+				dates[i], dates[j] = dates[j], dates[i]
+			}
+			return dates, !d.Equal(start)
+		}
+		dates = append(dates, d)
+	}
+}
+
+// lengthen combines a stub with the adjacent regular period, if long is true
+// and there is a regular period to combine it with.
+func lengthen(dates []civil.Date, long bool, initial bool) []civil.Date {
+	if !long || len(dates) < 3 {
+		return dates
+	}
+	if initial {
+		return append(dates[:1], dates[2:]...)
+	}
+	return append(dates[:len(dates)-2], dates[len(dates)-1])
+}
+
+// withEnds adds Start and End to dates generated between the regular dates,
+// if they are not already present.
+func (s Schedule) withEnds(dates []civil.Date) []civil.Date {
+	if !dates[0].Equal(s.Start) {
+		dates = append([]civil.Date{s.Start}, dates...)
+	}
+	if !dates[len(dates)-1].Equal(s.End) {
+		dates = append(dates, s.End)
+	}
+	return dates
+}
+
+// generate returns the date n periods after from, rolled according to the
+// roll convention. Generating each date from the same anchor avoids the drift
+// that would occur if dates in short months were used to generate later dates.
+func (s Schedule) generate(from civil.Date, n int) civil.Date {
+	freq := s.Frequency
+	months := (freq.Years*12 + freq.Months) * n
+	if months == 0 {
+		return from.AddDate(0, 0, freq.Days*n)
+	}
+	year, month, day := from.Date()
+	first := civil.DateFor(year, month, 1).AddDate(0, months, 0)
+	year, month, _ = first.Date()
+	switch {
+	case s.Roll == RollEOM:
+		day = 31
+	case s.Roll == RollIMM:
+		return thirdWednesday(year, month).AddDate(0, 0, freq.Days*n)
+	case s.Roll > 0:
+		day = int(s.Roll)
+	}
+	if last := daysIn(year, month); day > last {
+		day = last
+	}
+	return civil.DateFor(year, month, day).AddDate(0, 0, freq.Days*n)
+}
+
+// daysIn returns the number of days in the month.
+func daysIn(year int, month time.Month) int {
+	return civil.DateFor(year, month+1, 0).Day()
+}
+
+// thirdWednesday returns the third Wednesday of the month.
+func thirdWednesday(year int, month time.Month) civil.Date {
+	first := civil.DateFor(year, month, 1)
+	offset := (int(time.Wednesday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+14)
+}
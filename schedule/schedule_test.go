@@ -0,0 +1,214 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jjeffery/civil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseDate(s string) civil.Date {
+	d, err := civil.ParseDate(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return d
+}
+
+func formatDates(dates []civil.Date) string {
+	var s []string
+	for _, d := range dates {
+		s = append(s, d.String())
+	}
+	return strings.Join(s, " ")
+}
+
+func TestUnadjusted(t *testing.T) {
+	d := mustParseDate
+	quarterly := civil.PeriodOf(0, 3, 0)
+	monthly := civil.PeriodOf(0, 1, 0)
+	testCases := []struct {
+		Name     string
+		Schedule Schedule
+		Expected string
+	}{
+		{
+			Name:     "short initial",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-03-31"), Frequency: quarterly},
+			Expected: "2021-01-15 2021-03-31 2021-06-30 2021-09-30 2021-12-31 2022-03-31",
+		},
+		{
+			Name:     "long initial",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-03-31"), Frequency: quarterly, Stub: LongInitial},
+			Expected: "2021-01-15 2021-06-30 2021-09-30 2021-12-31 2022-03-31",
+		},
+		{
+			Name:     "short final",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-03-31"), Frequency: quarterly, Stub: ShortFinal},
+			Expected: "2021-01-15 2021-04-15 2021-07-15 2021-10-15 2022-01-15 2022-03-31",
+		},
+		{
+			Name:     "long final",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-03-31"), Frequency: quarterly, Stub: LongFinal},
+			Expected: "2021-01-15 2021-04-15 2021-07-15 2021-10-15 2022-03-31",
+		},
+		{
+			Name:     "no stub",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-01-15"), Frequency: civil.PeriodOf(0, 6, 0), Stub: LongInitial},
+			Expected: "2021-01-15 2021-07-15 2022-01-15",
+		},
+		{
+			Name:     "annual",
+			Schedule: Schedule{Start: d("2020-02-29"), End: d("2023-02-28"), Frequency: civil.PeriodOf(1, 0, 0), Stub: ShortFinal},
+			Expected: "2020-02-29 2021-02-28 2022-02-28 2023-02-28",
+		},
+		{
+			Name:     "no drift after short month",
+			Schedule: Schedule{Start: d("2021-01-31"), End: d("2021-05-31"), Frequency: monthly, Stub: ShortFinal},
+			Expected: "2021-01-31 2021-02-28 2021-03-31 2021-04-30 2021-05-31",
+		},
+		{
+			Name:     "no roll from end of February",
+			Schedule: Schedule{Start: d("2021-02-28"), End: d("2021-06-30"), Frequency: monthly, Stub: ShortFinal},
+			Expected: "2021-02-28 2021-03-28 2021-04-28 2021-05-28 2021-06-28 2021-06-30",
+		},
+		{
+			Name:     "end of month roll",
+			Schedule: Schedule{Start: d("2021-02-28"), End: d("2021-06-30"), Frequency: monthly, Roll: RollEOM, Stub: ShortFinal},
+			Expected: "2021-02-28 2021-03-31 2021-04-30 2021-05-31 2021-06-30",
+		},
+		{
+			Name:     "IMM roll",
+			Schedule: Schedule{Start: d("2021-03-17"), End: d("2021-12-15"), Frequency: quarterly, Roll: RollIMM, Stub: ShortFinal},
+			Expected: "2021-03-17 2021-06-16 2021-09-15 2021-12-15",
+		},
+		{
+			Name:     "fixed day roll",
+			Schedule: Schedule{Start: d("2021-01-05"), End: d("2021-06-20"), Frequency: quarterly, Roll: 20},
+			Expected: "2021-01-05 2021-03-20 2021-06-20",
+		},
+		{
+			Name:     "weekly",
+			Schedule: Schedule{Start: d("2021-03-01"), End: d("2021-03-24"), Frequency: civil.PeriodOf(0, 0, 7)},
+			Expected: "2021-03-01 2021-03-03 2021-03-10 2021-03-17 2021-03-24",
+		},
+		{
+			Name:     "zero frequency",
+			Schedule: Schedule{Start: d("2021-01-15"), End: d("2022-03-31")},
+			Expected: "2021-01-15 2022-03-31",
+		},
+		{
+			Name: "first and last regular",
+			Schedule: Schedule{Start: d("2021-01-10"), End: d("2021-10-01"), Frequency: quarterly,
+				FirstRegular: d("2021-03-15"), LastRegular: d("2021-09-15")},
+			Expected: "2021-01-10 2021-03-15 2021-06-15 2021-09-15 2021-10-01",
+		},
+		{
+			Name: "first regular",
+			Schedule: Schedule{Start: d("2021-01-10"), End: d("2021-10-01"), Frequency: quarterly,
+				FirstRegular: d("2021-03-15")},
+			Expected: "2021-01-10 2021-03-15 2021-06-15 2021-09-15 2021-10-01",
+		},
+		{
+			Name: "first regular with long final",
+			Schedule: Schedule{Start: d("2021-01-10"), End: d("2021-10-01"), Frequency: quarterly,
+				FirstRegular: d("2021-03-15"), Stub: LongFinal},
+			Expected: "2021-01-10 2021-03-15 2021-06-15 2021-10-01",
+		},
+		{
+			Name: "last regular",
+			Schedule: Schedule{Start: d("2021-01-10"), End: d("2021-10-01"), Frequency: quarterly,
+				LastRegular: d("2021-09-15")},
+			Expected: "2021-01-10 2021-03-15 2021-06-15 2021-09-15 2021-10-01",
+		},
+		{
+			Name: "last regular with long initial",
+			Schedule: Schedule{Start: d("2021-01-10"), End: d("2021-10-01"), Frequency: quarterly,
+				LastRegular: d("2021-09-15"), Stub: LongInitial},
+			Expected: "2021-01-10 2021-06-15 2021-09-15 2021-10-01",
+		},
+	}
+	for _, tc := range testCases {
+		dates, err := tc.Schedule.Unadjusted()
+		if !assert.NoError(t, err, tc.Name) {
+			continue
+		}
+		assert.Equal(t, tc.Expected, formatDates(dates), tc.Name)
+	}
+}
+
+func TestAdjusted(t *testing.T) {
+	d := mustParseDate
+	cal := civil.NewBusinessCalendar(civil.Weekend, civil.HolidayDates(d("2022-01-31")))
+	s := Schedule{
+		Start:     d("2022-01-31"),
+		End:       d("2022-07-31"),
+		Frequency: civil.PeriodOf(0, 3, 0),
+		Roll:      RollEOM,
+		Calendar:  cal,
+	}
+	testCases := []struct {
+		Convention civil.BusinessDayConvention
+		Expected   string
+	}{
+		{civil.Unadjusted, "2022-01-31 2022-04-30 2022-07-31"},
+		{civil.Following, "2022-02-01 2022-05-02 2022-08-01"},
+		{civil.ModifiedFollowing, "2022-01-28 2022-04-29 2022-07-29"},
+		{civil.Preceding, "2022-01-28 2022-04-29 2022-07-29"},
+	}
+	for _, tc := range testCases {
+		s.Convention = tc.Convention
+		dates, err := s.Adjusted()
+		if !assert.NoError(t, err, tc.Convention.String()) {
+			continue
+		}
+		assert.Equal(t, tc.Expected, formatDates(dates), tc.Convention.String())
+	}
+
+	s.Calendar = nil
+	dates, err := s.Adjusted()
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-01-31 2022-04-30 2022-07-31", formatDates(dates))
+}
+
+func TestErrors(t *testing.T) {
+	d := mustParseDate
+	quarterly := civil.PeriodOf(0, 3, 0)
+	testCases := []struct {
+		Name     string
+		Schedule Schedule
+	}{
+		{"end before start", Schedule{Start: d("2021-06-01"), End: d("2021-01-01"), Frequency: quarterly}},
+		{"end equals start", Schedule{Start: d("2021-06-01"), End: d("2021-06-01"), Frequency: quarterly}},
+		{"negative frequency", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly.Negate()}},
+		{"first regular before start", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly,
+			FirstRegular: d("2020-12-01")}},
+		{"last regular after end", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly,
+			LastRegular: d("2021-07-01")}},
+		{"first regular at end", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly,
+			FirstRegular: d("2021-06-01")}},
+		{"not regular", Schedule{Start: d("2021-01-01"), End: d("2021-12-01"), Frequency: quarterly,
+			FirstRegular: d("2021-02-01"), LastRegular: d("2021-10-01")}},
+		{"roll after day 31", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly, Roll: 99}},
+		{"negative roll", Schedule{Start: d("2021-01-01"), End: d("2021-06-01"), Frequency: quarterly, Roll: -5}},
+	}
+	for _, tc := range testCases {
+		_, err := tc.Schedule.Unadjusted()
+		assert.Error(t, err, tc.Name)
+		_, err = tc.Schedule.Adjusted()
+		assert.Error(t, err, tc.Name)
+	}
+}
+
+func TestString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("none", RollNone.String())
+	assert.Equal("EOM", RollEOM.String())
+	assert.Equal("IMM", RollIMM.String())
+	assert.Equal("15", Roll(15).String())
+	assert.Equal("Roll(32)", Roll(32).String())
+	assert.Equal("short-initial", ShortInitial.String())
+	assert.Equal("long-final", LongFinal.String())
+	assert.Equal("Stub(9)", Stub(9).String())
+}
@@ -0,0 +1,288 @@
+package civil
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// pollInterval is the longest time a Scheduler waits before checking
+// the clock again. Regular checks mean that timers still fire on time, to
+// within the poll interval, when the system clock is changed or when the
+// location of the scheduler's clock changes.
+const pollInterval = time.Minute
+
+// Scheduler fires timers when the civil date-time of its clock reaches a
+// deadline. Because deadlines are civil date-times, a timer for
+// 2021-03-01T08:00:00 fires at 08:00 in whatever location the clock has at
+// the time, and the instant is recalculated if the location changes.
+//
+// A deadline that is skipped because clocks are turned forward fires as
+// soon as the clock passes it. A deadline that is repeated because clocks
+// are turned back fires only on its first occurrence.
+//
+// A Scheduler is safe for concurrent use by multiple goroutines.
+type Scheduler struct {
+	clock  Clock
+	manual bool // the clock is a manualClock, so real time is not used
+
+	mutex  sync.Mutex
+	timers timerHeap
+	wake   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// NewScheduler returns a scheduler that uses clock, which is usually
+// a SystemClock, or a FakeClock for testing. The scheduler runs
+// a goroutine until it is closed.
+func NewScheduler(clock Clock) *Scheduler {
+	s := &Scheduler{
+		clock: clock,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	if c, ok := clock.(manualClock); ok {
+		// any change to the clock wakes the scheduler, including a change
+		// made after the scheduler reads the clock but before it sleeps,
+		// because the wake channel is buffered
+		c.watch(s.wake)
+		s.manual = true
+	}
+	go s.run()
+	return s
+}
+
+// Close stops the scheduler. Timers that have not fired will not fire.
+func (s *Scheduler) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+		if c, ok := s.clock.(manualClock); ok {
+			c.unwatch(s.wake)
+		}
+	}
+}
+
+// Check causes the scheduler to check its clock immediately, rather than
+// waiting for the next timer or poll. Call it when the location of the
+// clock is known to have changed.
+func (s *Scheduler) Check() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// NewTimer returns a timer that sends the civil date-time at which it
+// fires on its channel C once the clock reaches deadline.
+func (s *Scheduler) NewTimer(deadline DateTime) *Timer {
+	c := make(chan DateTime, 1)
+	t := &Timer{C: c, c: c, s: s}
+	s.add(t, deadline)
+	return t
+}
+
+// AfterFunc returns a timer that calls f in its own goroutine once the
+// clock reaches deadline.
+func (s *Scheduler) AfterFunc(deadline DateTime, f func()) *Timer {
+	t := &Timer{f: func(DateTime) { f() }, s: s}
+	s.add(t, deadline)
+	return t
+}
+
+// NewDailyTimer returns a timer that sends the civil date-time on its channel
+// C each time the civil date of the clock changes, which is normally at midnight.
+// Like a time.Ticker, it drops values if the receiver falls behind.
+// Stop the timer when it is no longer needed.
+func (s *Scheduler) NewDailyTimer() *Timer {
+	c := make(chan DateTime, 1)
+	t := &Timer{C: c, c: c, s: s, daily: true}
+	s.add(t, s.nextMidnight())
+	return t
+}
+
+// Daily calls f in its own goroutine with the new civil date each time
+// the civil date of the clock changes, which is normally at midnight.
+// Stop the returned timer to cancel the calls.
+func (s *Scheduler) Daily(f func(today Date)) *Timer {
+	t := &Timer{f: func(dt DateTime) { f(dt.date()) }, s: s, daily: true}
+	s.add(t, s.nextMidnight())
+	return t
+}
+
+func (s *Scheduler) nextMidnight() DateTime {
+	return TodayFrom(s.clock).AddDate(0, 0, 1).midnight()
+}
+
+func (s *Scheduler) add(t *Timer, deadline DateTime) {
+	s.mutex.Lock()
+	t.deadline = deadline
+	heap.Push(&s.timers, t)
+	s.mutex.Unlock()
+	s.Check()
+}
+
+// remove removes t from the scheduler and reports whether it was pending.
+func (s *Scheduler) remove(t *Timer) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&s.timers, t.index)
+	return true
+}
+
+func (s *Scheduler) run() {
+	for {
+		now := s.clock.Now()
+		civilNow := DateTimeOf(now)
+		tomorrow := civilNow.date().AddDate(0, 0, 1).midnight()
+		wait := pollInterval
+
+		s.mutex.Lock()
+		for _, t := range s.timers {
+			if t.daily && t.deadline.After(tomorrow) {
+				// the clock has moved back to an earlier date
+				t.deadline = tomorrow
+				heap.Fix(&s.timers, t.index)
+			}
+		}
+		var due []*Timer
+		for len(s.timers) > 0 && !s.timers[0].deadline.After(civilNow) {
+			t := heap.Pop(&s.timers).(*Timer)
+			due = append(due, t)
+			if t.daily {
+				t.deadline = tomorrow
+				heap.Push(&s.timers, t)
+			}
+		}
+		if len(s.timers) > 0 {
+			if d := untilDeadline(s.timers[0].deadline, now); d < wait {
+				wait = d
+			}
+		}
+		s.mutex.Unlock()
+
+		for _, t := range due {
+			t.fire(civilNow)
+		}
+
+		if !s.sleep(wait) {
+			return
+		}
+	}
+}
+
+// sleep waits until wait has elapsed, or until the scheduler is woken.
+// A scheduler with a manual clock only wakes when the clock changes.
+// It returns false if the scheduler has been closed.
+func (s *Scheduler) sleep(wait time.Duration) bool {
+	var elapsed <-chan time.Time
+	if !s.manual {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		elapsed = timer.C
+	}
+	select {
+	case <-elapsed:
+	case <-s.wake:
+	case <-s.done:
+		return false
+	}
+	return true
+}
+
+// untilDeadline returns the time from now until the civil date-time deadline
+// occurs in now's location.
+func untilDeadline(deadline DateTime, now time.Time) time.Duration {
+	loc := now.Location()
+	w := lookupWallTime(deadline, loc)
+	var at time.Time
+	if len(w.valid) > 0 {
+		at = w.instant(w.valid[0], loc)
+	} else {
+		// the deadline is reached when clocks are turned forward
+		at = findTransition(w.earlier(loc), w.later(loc), w.before, w.after, loc).At
+	}
+	if d := at.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Timer is a single event, or a daily event, created by a Scheduler.
+type Timer struct {
+	// C receives the civil date-time when the timer fires. It is nil
+	// for timers created by AfterFunc and Daily.
+	C <-chan DateTime
+
+	c        chan DateTime
+	f        func(DateTime)
+	s        *Scheduler
+	daily    bool
+	deadline DateTime
+	index    int // index in the scheduler's heap, or -1
+}
+
+// Stop prevents the timer from firing. It returns true if the call stops
+// the timer, and false if the timer has already fired or been stopped.
+// Stop does not close the channel C.
+func (t *Timer) Stop() bool {
+	return t.s.remove(t)
+}
+
+// Reset changes the timer to fire at deadline. It returns true if the timer
+// had been pending, and false if it had fired or been stopped.
+func (t *Timer) Reset(deadline DateTime) bool {
+	pending := t.s.remove(t)
+	t.s.add(t, deadline)
+	return pending
+}
+
+func (t *Timer) fire(now DateTime) {
+	if t.f != nil {
+		go t.f(now)
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+// timerHeap implements heap.Interface, ordering timers by deadline.
+type timerHeap []*Timer
+
+func (h timerHeap) Len() int {
+	return len(h)
+}
+
+func (h timerHeap) Less(i, j int) bool {
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	t := x.(*Timer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
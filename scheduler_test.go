@@ -0,0 +1,246 @@
+package civil
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(c <-chan DateTime) (DateTime, bool) {
+	select {
+	case dt := <-c:
+		return dt, true
+	case <-time.After(time.Second):
+		return DateTime{}, false
+	}
+}
+
+func notReceived(c <-chan DateTime) bool {
+	select {
+	case <-c:
+		return false
+	case <-time.After(20 * time.Millisecond):
+		return true
+	}
+}
+
+func TestSchedulerTimer(t *testing.T) {
+	assert := assert.New(t)
+	clock := NewFakeClock(time.Date(2021, 3, 1, 7, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	timer := s.NewTimer(mustParseDateTime("2021-03-01T08:00"))
+	assert.True(notReceived(timer.C))
+
+	clock.Advance(59 * time.Minute)
+	assert.True(notReceived(timer.C))
+
+	clock.Advance(time.Minute)
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-01T08:00:00", dt.String())
+	assert.False(timer.Stop())
+
+	// a deadline in the past fires immediately
+	timer = s.NewTimer(mustParseDateTime("2021-02-01T08:00"))
+	dt, ok = receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-01T08:00:00", dt.String())
+}
+
+func TestSchedulerStopReset(t *testing.T) {
+	assert := assert.New(t)
+	clock := NewFakeClock(time.Date(2021, 3, 1, 7, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	timer := s.NewTimer(mustParseDateTime("2021-03-01T08:00"))
+	assert.True(timer.Stop())
+	assert.False(timer.Stop())
+	clock.Advance(2 * time.Hour)
+	assert.True(notReceived(timer.C))
+
+	assert.False(timer.Reset(mustParseDateTime("2021-03-01T10:00")))
+	assert.True(timer.Reset(mustParseDateTime("2021-03-01T09:30")))
+	clock.Advance(30 * time.Minute)
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-01T09:30:00", dt.String())
+}
+
+func TestSchedulerAfterFunc(t *testing.T) {
+	assert := assert.New(t)
+	clock := NewFakeClock(time.Date(2021, 3, 1, 7, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	called := make(chan struct{})
+	timer := s.AfterFunc(mustParseDateTime("2021-03-01T08:00"), func() { close(called) })
+	assert.Nil(timer.C)
+	clock.Advance(time.Hour)
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Error("function not called")
+	}
+}
+
+func TestSchedulerZoneChange(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	clock := NewFakeClock(time.Date(2021, 3, 1, 7, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	// 08:00 in UTC is 19:00 in Sydney, so moving to Sydney makes it due
+	timer := s.NewTimer(mustParseDateTime("2021-03-01T08:00"))
+	clock.Set(clock.Now().In(sydney))
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-01T18:00:00", dt.String())
+
+	// moving back to UTC makes 2021-03-01T12:00 five hours away again
+	timer = s.NewTimer(mustParseDateTime("2021-03-01T12:00"))
+	clock.Set(clock.Now().In(time.UTC))
+	clock.Advance(4 * time.Hour)
+	assert.True(notReceived(timer.C))
+	clock.Advance(time.Hour)
+	dt, ok = receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-01T12:00:00", dt.String())
+}
+
+func TestSchedulerSetRace(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	start := time.Date(2021, 3, 1, 7, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(clock)
+
+	// each Set may happen while the scheduler is between reading the clock
+	// and sleeping, and must not be missed
+	for i := 0; i < 100; i++ {
+		clock.Set(start)
+		timer := s.NewTimer(mustParseDateTime("2021-03-01T08:00"))
+		clock.Set(start.In(sydney))
+		_, ok := receive(timer.C)
+		if !assert.True(ok, "iteration %d", i) {
+			break
+		}
+	}
+
+	assert.Len(clock.watchers, 1)
+	s.Close()
+	assert.Len(clock.watchers, 0)
+}
+
+func TestSchedulerGap(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	// clocks turned forward from 02:00 to 03:00
+	clock := NewFakeClock(time.Date(2021, 10, 3, 1, 50, 0, 0, sydney))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	timer := s.NewTimer(mustParseDateTime("2021-10-03T02:30"))
+	clock.Advance(9 * time.Minute)
+	assert.True(notReceived(timer.C))
+	clock.Advance(time.Minute)
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-10-03T03:00:00", dt.String())
+}
+
+func TestSchedulerOverlap(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	// clocks turned back from 03:00 to 02:00
+	clock := NewFakeClock(time.Date(2021, 4, 4, 2, 0, 0, 0, time.UTC).Add(-11 * time.Hour).In(sydney))
+	assert.Equal("2021-04-04T02:00:00", NowFrom(clock).String())
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	timer := s.NewTimer(mustParseDateTime("2021-04-04T02:30"))
+	clock.Advance(30 * time.Minute)
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-04-04T02:30:00", dt.String())
+
+	// second occurrence does not fire again
+	clock.Advance(time.Hour)
+	assert.Equal("2021-04-04T02:30:00", NowFrom(clock).String())
+	assert.True(notReceived(timer.C))
+}
+
+func TestSchedulerDaily(t *testing.T) {
+	assert := assert.New(t)
+	clock := NewFakeClock(time.Date(2021, 3, 1, 23, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	timer := s.NewDailyTimer()
+	var mutex sync.Mutex
+	var dates []string
+	called := make(chan struct{}, 10)
+	s.Daily(func(today Date) {
+		mutex.Lock()
+		dates = append(dates, today.String())
+		mutex.Unlock()
+		called <- struct{}{}
+	})
+
+	for _, want := range []string{"2021-03-02T00:00:00", "2021-03-03T00:00:00"} {
+		clock.Advance(time.Hour)
+		dt, ok := receive(timer.C)
+		assert.True(ok)
+		assert.Equal(want, dt.String())
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Error("function not called")
+		}
+		clock.Advance(23 * time.Hour)
+		assert.True(notReceived(timer.C))
+	}
+	mutex.Lock()
+	assert.Equal([]string{"2021-03-02", "2021-03-03"}, dates)
+	mutex.Unlock()
+
+	// moving back a day fires at the next midnight
+	clock.Set(clock.Now().Add(-48 * time.Hour))
+	assert.True(notReceived(timer.C))
+	clock.Advance(time.Hour)
+	dt, ok := receive(timer.C)
+	assert.True(ok)
+	assert.Equal("2021-03-02T00:00:00", dt.String())
+
+	assert.True(timer.Stop())
+	clock.Advance(24 * time.Hour)
+	assert.True(notReceived(timer.C))
+}
+
+func TestSchedulerConcurrent(t *testing.T) {
+	clock := NewFakeClock(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(clock)
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	start := mustParseDateTime("2021-03-01T00:00")
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			timer := s.NewTimer(start.Add(time.Duration(i) * time.Minute))
+			if _, ok := receive(timer.C); !ok {
+				t.Errorf("timer %d did not fire", i)
+			}
+		}(i)
+	}
+	for i := 0; i < 60; i++ {
+		clock.Advance(time.Minute)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package civil
+
+// Split cuts the date-time range [start, end) at each boundary of unit, as
+// determined by StartOf, and returns the pieces in order. Each piece
+// is a DateTimeRange, so its length is available from its Duration method.
+//
+// For example, splitting 2021-03-01T22:00:00 to 2021-03-02T06:00:00 by
+// UnitDay returns two pieces: 22:00 to midnight and midnight to 06:00.
+// If end is not after start, Split returns nil.
+func Split(start, end DateTime, unit Unit) []DateTimeRange {
+	var pieces []DateTimeRange
+	for start.Before(end) {
+		next := nextStartOf(start.date(), unit).midnight()
+		if next.After(end) {
+			next = end
+		}
+		pieces = append(pieces, DateTimeRange{Start: start, End: next})
+		start = next
+	}
+	return pieces
+}
+
+// Split cuts r at each boundary of unit. See the Split function for details.
+func (r DateTimeRange) Split(unit Unit) []DateTimeRange {
+	return Split(r.Start, r.End, unit)
+}
+
+// SplitDates cuts the range of dates from start to end inclusive at each
+// boundary of unit, as determined by StartOf, and returns the pieces in order.
+// Each piece is a DateRange, so the number of days it covers is available
+// from its Days method.
+//
+// For example, splitting 2021-03-29 to 2021-04-02 by UnitMonth returns
+// 2021-03-29/2021-03-31 and 2021-04-01/2021-04-02.
+// If end is before start, SplitDates returns nil.
+func SplitDates(start, end Date, unit Unit) []DateRange {
+	var pieces []DateRange
+	for !start.After(end) {
+		next := nextStartOf(start, unit)
+		last := next.AddDate(0, 0, -1)
+		if last.After(end) {
+			last = end
+		}
+		pieces = append(pieces, DateRange{Start: start, End: last})
+		start = next
+	}
+	return pieces
+}
+
+// Split cuts r at each boundary of unit. See the SplitDates function for details.
+func (r DateRange) Split(unit Unit) []DateRange {
+	return SplitDates(r.Start, r.End, unit)
+}
@@ -0,0 +1,142 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rangeStrings(ranges []DateTimeRange) []string {
+	var s []string
+	for _, r := range ranges {
+		s = append(s, r.String())
+	}
+	return s
+}
+
+func TestSplit(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Start, End string
+		Unit       Unit
+		Expected   []string
+	}{
+		{
+			Start: "2021-03-01T22:00",
+			End:   "2021-03-02T06:00",
+			Unit:  UnitDay,
+			Expected: []string{
+				"2021-03-01T22:00:00/2021-03-02T00:00:00",
+				"2021-03-02T00:00:00/2021-03-02T06:00:00",
+			},
+		},
+		{
+			Start: "2021-03-01T09:00",
+			End:   "2021-03-01T17:00",
+			Unit:  UnitDay,
+			Expected: []string{
+				"2021-03-01T09:00:00/2021-03-01T17:00:00",
+			},
+		},
+		{
+			Start: "2021-03-06T20:00",
+			End:   "2021-03-15T00:00",
+			Unit:  UnitWeek,
+			Expected: []string{
+				"2021-03-06T20:00:00/2021-03-08T00:00:00",
+				"2021-03-08T00:00:00/2021-03-15T00:00:00",
+			},
+		},
+		{
+			Start: "2020-12-31T12:00",
+			End:   "2021-04-01T12:00",
+			Unit:  UnitQuarter,
+			Expected: []string{
+				"2020-12-31T12:00:00/2021-01-01T00:00:00",
+				"2021-01-01T00:00:00/2021-04-01T00:00:00",
+				"2021-04-01T00:00:00/2021-04-01T12:00:00",
+			},
+		},
+		{
+			Start: "2021-01-31T12:00",
+			End:   "2021-03-01T00:00",
+			Unit:  UnitMonth,
+			Expected: []string{
+				"2021-01-31T12:00:00/2021-02-01T00:00:00",
+				"2021-02-01T00:00:00/2021-03-01T00:00:00",
+			},
+		},
+		{
+			Start: "2020-06-01T00:00",
+			End:   "2021-06-01T00:00",
+			Unit:  UnitYear,
+			Expected: []string{
+				"2020-06-01T00:00:00/2021-01-01T00:00:00",
+				"2021-01-01T00:00:00/2021-06-01T00:00:00",
+			},
+		},
+		{
+			Start: "2021-03-02T06:00",
+			End:   "2021-03-01T22:00",
+			Unit:  UnitDay,
+		},
+	}
+
+	for _, tc := range testCases {
+		r := DateTimeRangeFor(mustParseDateTime(tc.Start), mustParseDateTime(tc.End))
+		assert.Equal(tc.Expected, rangeStrings(r.Split(tc.Unit)), r.String())
+	}
+
+	pieces := Split(mustParseDateTime("2021-03-01T22:00"), mustParseDateTime("2021-03-02T06:00"), UnitDay)
+	assert.Equal(2*time.Hour, pieces[0].Duration())
+	assert.Equal(6*time.Hour, pieces[1].Duration())
+}
+
+func TestSplitDates(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Start, End string
+		Unit       Unit
+		Expected   []string
+	}{
+		{
+			Start:    "2021-03-29",
+			End:      "2021-04-02",
+			Unit:     UnitMonth,
+			Expected: []string{"2021-03-29/2021-03-31", "2021-04-01/2021-04-02"},
+		},
+		{
+			Start:    "2021-03-05",
+			End:      "2021-03-16",
+			Unit:     UnitWeek,
+			Expected: []string{"2021-03-05/2021-03-07", "2021-03-08/2021-03-14", "2021-03-15/2021-03-16"},
+		},
+		{
+			Start:    "2021-03-05",
+			End:      "2021-03-07",
+			Unit:     UnitDay,
+			Expected: []string{"2021-03-05/2021-03-05", "2021-03-06/2021-03-06", "2021-03-07/2021-03-07"},
+		},
+		{
+			Start:    "2021-03-05",
+			End:      "2021-03-05",
+			Unit:     UnitYear,
+			Expected: []string{"2021-03-05/2021-03-05"},
+		},
+		{
+			Start: "2021-03-05",
+			End:   "2021-03-04",
+			Unit:  UnitYear,
+		},
+	}
+
+	for _, tc := range testCases {
+		r := DateRangeFor(mustParseDate(tc.Start), mustParseDate(tc.End))
+		var actual []string
+		for _, piece := range r.Split(tc.Unit) {
+			actual = append(actual, piece.String())
+		}
+		assert.Equal(tc.Expected, actual, r.String())
+	}
+}
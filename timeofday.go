@@ -0,0 +1,24 @@
+package civil
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeOfDayRange is a range of times of day from Start up to End, as
+// durations since midnight. In OpeningHours, a range whose End is not after
+// Start continues past midnight into the following day, so 22:00 to 02:00
+// is open until 2am the next morning.
+type TimeOfDayRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// String returns the range in the format "09:00-17:30".
+func (r TimeOfDayRange) String() string {
+	return formatTimeOfDay(r.Start) + "-" + formatTimeOfDay(r.End)
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
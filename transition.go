@@ -0,0 +1,144 @@
+package civil
+
+import "time"
+
+// transitionStep is the interval between instants that are checked for
+// a change in offset when searching for transitions. It must be shorter
+// than the interval between any two transitions in the same zone.
+const transitionStep = 6 * time.Hour
+
+// Transition describes a change in the offset of a time zone, such as
+// the start or end of daylight saving time.
+type Transition struct {
+	// At is the instant at which the offset changes, in the location.
+	At time.Time
+
+	// OffsetBefore and OffsetAfter are the offsets, in seconds east
+	// of UTC, in effect before and after the transition.
+	OffsetBefore int
+	OffsetAfter  int
+
+	// Kind is WallTimeGap if clocks are turned forward, so that some
+	// civil date-times are skipped, or WallTimeOverlap if clocks are
+	// turned back, so that some civil date-times are repeated.
+	Kind WallTimeKind
+
+	// Affected is the range of civil date-times that are skipped
+	// or repeated because of the transition.
+	Affected DateTimeRange
+}
+
+// newTransition returns the transition at instant at.
+func newTransition(at time.Time, before, after int) Transition {
+	tr := Transition{
+		At:           at,
+		OffsetBefore: before,
+		OffsetAfter:  after,
+		Kind:         WallTimeGap,
+	}
+	utc := DateTimeOf(at.UTC())
+	wallBefore := utc.Add(time.Duration(before) * time.Second)
+	wallAfter := utc.Add(time.Duration(after) * time.Second)
+	if after < before {
+		tr.Kind = WallTimeOverlap
+		wallBefore, wallAfter = wallAfter, wallBefore
+	}
+	tr.Affected = DateTimeRange{Start: wallBefore, End: wallAfter}
+	return tr
+}
+
+// TransitionsBetween returns the transitions in loc that skip or repeat
+// any civil date-time on the dates from a to b inclusive, in order.
+// It uses only the information available from loc.
+func TransitionsBetween(a, b Date, loc *time.Location) []Transition {
+	var transitions []Transition
+	if b.Before(a) {
+		return transitions
+	}
+	days := DateTimeRange{Start: a.midnight(), End: b.AddDate(0, 0, 1).midnight()}
+
+	// Allow a day either side, because the instants of a civil date
+	// depend on the offset.
+	t := a.AddDate(0, 0, -1).StartIn(loc)
+	end := b.AddDate(0, 0, 2).StartIn(loc)
+	_, offset := t.Zone()
+	for t.Before(end) {
+		next := t.Add(transitionStep)
+		_, nextOffset := next.Zone()
+		if nextOffset != offset {
+			tr := findTransition(t, next, offset, nextOffset, loc)
+			if tr.Affected.Overlaps(days) {
+				transitions = append(transitions, tr)
+			}
+		}
+		t, offset = next, nextOffset
+	}
+	return transitions
+}
+
+// findTransition uses a binary search to find the instant between lo and hi
+// at which the offset changes from before to after.
+func findTransition(lo, hi time.Time, before, after int, loc *time.Location) Transition {
+	loSec, hiSec := lo.Unix(), hi.Unix()
+	for hiSec-loSec > 1 {
+		mid := loSec + (hiSec-loSec)/2
+		if _, offset := time.Unix(mid, 0).In(loc).Zone(); offset == before {
+			loSec = mid
+		} else {
+			hiSec = mid
+		}
+	}
+	return newTransition(time.Unix(hiSec, 0).In(loc), before, after)
+}
+
+// ScheduleAudit describes how a civil date-time in a schedule maps onto a time zone.
+type ScheduleAudit struct {
+	DateTime DateTime
+
+	// Kind is WallTimeNormal if the date-time occurs once, WallTimeGap if it
+	// is skipped and WallTimeOverlap if it is repeated.
+	Kind WallTimeKind
+
+	// Transition is the transition that causes the date-time to be skipped
+	// or repeated. It is nil if Kind is WallTimeNormal.
+	Transition *Transition
+
+	// Resolution is the suggested way to resolve the date-time. It is
+	// DisambiguateLater for a skipped date-time, so that it is moved to
+	// the end of the gap, and DisambiguateEarlier for a repeated date-time,
+	// so that it happens only at the first occurrence.
+	Resolution Disambiguation
+
+	// Suggested is the instant that results from the suggested resolution.
+	Suggested time.Time
+}
+
+// AuditSchedule classifies each of the civil date-times as normal, skipped
+// or repeated in loc, and suggests a resolution for those that are skipped
+// or repeated. The result has one entry for each date-time, in the same order.
+func AuditSchedule(times []DateTime, loc *time.Location) []ScheduleAudit {
+	audits := make([]ScheduleAudit, len(times))
+	for i, dt := range times {
+		audit := ScheduleAudit{
+			DateTime:   dt,
+			Kind:       lookupWallTime(dt, loc).kind(),
+			Resolution: DisambiguateEarlier,
+		}
+		if audit.Kind == WallTimeGap {
+			audit.Resolution = DisambiguateLater
+		}
+		audit.Suggested, _ = dt.In(loc, audit.Resolution)
+		if audit.Kind != WallTimeNormal {
+			d := dt.date()
+			for _, tr := range TransitionsBetween(d, d, loc) {
+				if tr.Affected.Contains(dt) {
+					tr := tr
+					audit.Transition = &tr
+					break
+				}
+			}
+		}
+		audits[i] = audit
+	}
+	return audits
+}
@@ -0,0 +1,83 @@
+package civil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionsBetween(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+
+	transitions := TransitionsBetween(mustParseDate("2021-01-01"), mustParseDate("2021-12-31"), sydney)
+	if assert.Len(transitions, 2) {
+		tr := transitions[0]
+		assert.Equal("2021-04-04T02:00:00+10:00", tr.At.Format(time.RFC3339))
+		assert.Equal(11*3600, tr.OffsetBefore)
+		assert.Equal(10*3600, tr.OffsetAfter)
+		assert.Equal(WallTimeOverlap, tr.Kind)
+		assert.Equal("2021-04-04T02:00:00/2021-04-04T03:00:00", tr.Affected.String())
+
+		tr = transitions[1]
+		assert.Equal("2021-10-03T03:00:00+11:00", tr.At.Format(time.RFC3339))
+		assert.Equal(10*3600, tr.OffsetBefore)
+		assert.Equal(11*3600, tr.OffsetAfter)
+		assert.Equal(WallTimeGap, tr.Kind)
+		assert.Equal("2021-10-03T02:00:00/2021-10-03T03:00:00", tr.Affected.String())
+	}
+
+	assert.Len(TransitionsBetween(mustParseDate("2021-10-03"), mustParseDate("2021-10-03"), sydney), 1)
+	assert.Len(TransitionsBetween(mustParseDate("2021-10-02"), mustParseDate("2021-10-02"), sydney), 0)
+	assert.Len(TransitionsBetween(mustParseDate("2021-10-04"), mustParseDate("2021-10-04"), sydney), 0)
+	assert.Len(TransitionsBetween(mustParseDate("2021-12-31"), mustParseDate("2021-01-01"), sydney), 0)
+	assert.Len(TransitionsBetween(mustParseDate("2021-01-01"), mustParseDate("2021-12-31"), time.UTC), 0)
+
+	// a transition at midnight affects the civil date on which the gap falls
+	santiago := mustLoadLocation("America/Santiago")
+	transitions = TransitionsBetween(mustParseDate("2021-09-05"), mustParseDate("2021-09-05"), santiago)
+	if assert.Len(transitions, 1) {
+		assert.Equal("2021-09-05T00:00:00/2021-09-05T01:00:00", transitions[0].Affected.String())
+	}
+	assert.Len(TransitionsBetween(mustParseDate("2021-09-04"), mustParseDate("2021-09-04"), santiago), 0)
+	transitions = TransitionsBetween(mustParseDate("2021-04-03"), mustParseDate("2021-04-03"), santiago)
+	if assert.Len(transitions, 1) {
+		assert.Equal("2021-04-03T23:00:00/2021-04-04T00:00:00", transitions[0].Affected.String())
+	}
+}
+
+func TestAuditSchedule(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	times := []DateTime{
+		mustParseDateTime("2021-10-02T02:30"),
+		mustParseDateTime("2021-10-03T02:30"),
+		mustParseDateTime("2021-04-04T02:30"),
+		mustParseDateTime("2021-04-04T03:00"),
+	}
+
+	audits := AuditSchedule(times, sydney)
+	assert.Len(audits, 4)
+
+	assert.Equal(WallTimeNormal, audits[0].Kind)
+	assert.Nil(audits[0].Transition)
+	assert.Equal("2021-10-02T02:30:00+10:00", audits[0].Suggested.Format(time.RFC3339))
+
+	assert.Equal(WallTimeGap, audits[1].Kind)
+	assert.Equal(DisambiguateLater, audits[1].Resolution)
+	assert.Equal("2021-10-03T03:30:00+11:00", audits[1].Suggested.Format(time.RFC3339))
+	if assert.NotNil(audits[1].Transition) {
+		assert.Equal(WallTimeGap, audits[1].Transition.Kind)
+	}
+
+	assert.Equal(WallTimeOverlap, audits[2].Kind)
+	assert.Equal(DisambiguateEarlier, audits[2].Resolution)
+	assert.Equal("2021-04-04T02:30:00+11:00", audits[2].Suggested.Format(time.RFC3339))
+	if assert.NotNil(audits[2].Transition) {
+		assert.Equal("2021-04-04T02:00:00+10:00", audits[2].Transition.At.Format(time.RFC3339))
+	}
+
+	assert.Equal(WallTimeNormal, audits[3].Kind)
+	assert.True(times[3].Equal(audits[3].DateTime))
+}
@@ -0,0 +1,89 @@
+package civil
+
+import (
+	"fmt"
+	"time"
+)
+
+// Unit is a calendar unit used for truncating dates and
+// splitting ranges at calendar boundaries.
+type Unit int
+
+// Calendar units. Weeks are ISO 8601 weeks, which start on Monday.
+// Quarters start in January, April, July and October.
+const (
+	UnitDay Unit = iota
+	UnitWeek
+	UnitMonth
+	UnitQuarter
+	UnitYear
+)
+
+var unitNames = []string{
+	UnitDay:     "day",
+	UnitWeek:    "week",
+	UnitMonth:   "month",
+	UnitQuarter: "quarter",
+	UnitYear:    "year",
+}
+
+// String returns the English name of the unit ("day", "week", ...).
+func (u Unit) String() string {
+	if u >= 0 && int(u) < len(unitNames) {
+		return unitNames[u]
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// StartOf returns the first date of the unit containing d.
+// For example, the start of the month containing 2021-03-17 is 2021-03-01,
+// and the start of its week is Monday 2021-03-15.
+func (d Date) StartOf(unit Unit) Date {
+	year, month, day := d.Date()
+	switch unit {
+	case UnitWeek:
+		// days since Monday
+		offset := (int(d.Weekday()) + 6) % 7
+		return DateFor(year, month, day-offset)
+	case UnitMonth:
+		return DateFor(year, month, 1)
+	case UnitQuarter:
+		return DateFor(year, month-(month-1)%3, 1)
+	case UnitYear:
+		return DateFor(year, time.January, 1)
+	}
+	return d
+}
+
+// StartOf returns the midnight that starts the unit containing dt.
+// For example, the start of the day containing 2021-03-01T22:00:00
+// is 2021-03-01T00:00:00.
+func (dt DateTime) StartOf(unit Unit) DateTime {
+	return dt.date().StartOf(unit).midnight()
+}
+
+// nextStartOf returns the first date of the unit following the one containing d.
+func nextStartOf(d Date, unit Unit) Date {
+	start := d.StartOf(unit)
+	switch unit {
+	case UnitWeek:
+		return start.AddDate(0, 0, 7)
+	case UnitMonth:
+		return start.AddDate(0, 1, 0)
+	case UnitQuarter:
+		return start.AddDate(0, 3, 0)
+	case UnitYear:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// date returns the date on which dt occurs.
+func (dt DateTime) date() Date {
+	return DateFor(dt.Date())
+}
+
+// midnight returns the date-time at the start of d.
+func (d Date) midnight() DateTime {
+	return DateTime{t: d.t}
+}
@@ -0,0 +1,48 @@
+package civil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateStartOf(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Date     string
+		Unit     Unit
+		Expected string
+	}{
+		{"2021-03-17", UnitDay, "2021-03-17"},
+		{"2021-03-17", UnitWeek, "2021-03-15"},
+		{"2021-03-15", UnitWeek, "2021-03-15"},
+		{"2021-03-21", UnitWeek, "2021-03-15"},
+		{"2021-01-01", UnitWeek, "2020-12-28"},
+		{"2021-03-17", UnitMonth, "2021-03-01"},
+		{"2021-03-17", UnitQuarter, "2021-01-01"},
+		{"2021-04-01", UnitQuarter, "2021-04-01"},
+		{"2021-12-31", UnitQuarter, "2021-10-01"},
+		{"2021-03-17", UnitYear, "2021-01-01"},
+	}
+
+	for _, tc := range testCases {
+		d := mustParseDate(tc.Date)
+		assert.Equal(tc.Expected, d.StartOf(tc.Unit).String(), "%s %s", tc.Date, tc.Unit)
+	}
+}
+
+func TestDateTimeStartOf(t *testing.T) {
+	assert := assert.New(t)
+	dt := mustParseDateTime("2021-03-17T22:15:30")
+	assert.Equal("2021-03-17T00:00:00", dt.StartOf(UnitDay).String())
+	assert.Equal("2021-03-15T00:00:00", dt.StartOf(UnitWeek).String())
+	assert.Equal("2021-03-01T00:00:00", dt.StartOf(UnitMonth).String())
+	assert.Equal("2021-01-01T00:00:00", dt.StartOf(UnitQuarter).String())
+	assert.Equal("2021-01-01T00:00:00", dt.StartOf(UnitYear).String())
+}
+
+func TestUnitString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("quarter", UnitQuarter.String())
+	assert.Equal("Unit(9)", Unit(9).String())
+}
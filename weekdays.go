@@ -0,0 +1,255 @@
+package civil
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+var errInvalidWeekdays = errors.New("invalid weekdays format")
+
+// Weekdays is a set of days of the week, stored as a bit mask
+// with bit n set when time.Weekday(n) is in the set.
+// It is useful for recurrence masks, opening hours and
+// weekend definitions.
+type Weekdays uint8
+
+// Commonly used sets of weekdays.
+const (
+	NoWeekdays     Weekdays = 0
+	AllWeekdays    Weekdays = 1<<7 - 1
+	Weekend        Weekdays = 1<<time.Saturday | 1<<time.Sunday
+	MondayToFriday Weekdays = AllWeekdays &^ Weekend
+)
+
+var weekdayNames = [][]string{
+	time.Sunday:    {"sun", "sunday", "su"},
+	time.Monday:    {"mon", "monday", "mo"},
+	time.Tuesday:   {"tue", "tuesday", "tu", "tues"},
+	time.Wednesday: {"wed", "wednesday", "we"},
+	time.Thursday:  {"thu", "thursday", "th", "thur", "thurs"},
+	time.Friday:    {"fri", "friday", "fr"},
+	time.Saturday:  {"sat", "saturday", "sa"},
+}
+
+// WeekdaysOf returns the set containing days.
+func WeekdaysOf(days ...time.Weekday) Weekdays {
+	var w Weekdays
+	for _, day := range days {
+		w = w.Add(day)
+	}
+	return w
+}
+
+// Has reports whether day is in w.
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w&weekdayBit(day) != 0
+}
+
+// Add returns the set containing the days in w together with day.
+func (w Weekdays) Add(day time.Weekday) Weekdays {
+	return w | weekdayBit(day)
+}
+
+// Remove returns the set containing the days in w except for day.
+func (w Weekdays) Remove(day time.Weekday) Weekdays {
+	return w &^ weekdayBit(day)
+}
+
+// Count returns the number of days in w.
+func (w Weekdays) Count() int {
+	return bits.OnesCount8(uint8(w & AllWeekdays))
+}
+
+// IsEmpty reports whether w contains no days.
+func (w Weekdays) IsEmpty() bool {
+	return w&AllWeekdays == 0
+}
+
+// Days returns the days in w in order, starting from first.
+// For example, to list days in ISO 8601 order, first is time.Monday.
+func (w Weekdays) Days(first time.Weekday) []time.Weekday {
+	var days []time.Weekday
+	for i := 0; i < 7; i++ {
+		day := (first + time.Weekday(i)) % 7
+		if w.Has(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// String returns a string representation of w, listing the
+// days starting from Monday. Runs of three or more consecutive
+// days are shown as a range, for example "Mon-Fri,Sun".
+func (w Weekdays) String() string {
+	return w.format(weekdayShortName)
+}
+
+// format returns w as a list of days and ranges of days, using name
+// for the name of each day.
+func (w Weekdays) format(name func(time.Weekday) string) string {
+	var parts []string
+	for day := 0; day < 7; {
+		if !w.Has(isoWeekday(day)) {
+			day++
+			continue
+		}
+		end := day
+		for end+1 < 7 && w.Has(isoWeekday(end+1)) {
+			end++
+		}
+		if end-day >= 2 {
+			parts = append(parts, name(isoWeekday(day))+"-"+name(isoWeekday(end)))
+		} else {
+			for i := day; i <= end; i++ {
+				parts = append(parts, name(isoWeekday(i)))
+			}
+		}
+		day = end + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseWeekdays parses a list of days and ranges of days separated by
+// commas, for example "Mon-Fri,Sun". Day names are case-insensitive and
+// may be full names ("Monday"), three letter abbreviations ("Mon") or
+// two letter abbreviations ("Mo"). A range may wrap around the end of the
+// week, so "Fri-Mon" is Friday, Saturday, Sunday and Monday.
+func ParseWeekdays(s string) (Weekdays, error) {
+	var w Weekdays
+	s = strings.Trim(s, " \t\"'")
+	if s == "" {
+		return w, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		names := strings.Split(part, "-")
+		if len(names) > 2 {
+			return NoWeekdays, errInvalidWeekdays
+		}
+		first, ok := lookupWeekday(names[0])
+		if !ok {
+			return NoWeekdays, errInvalidWeekdays
+		}
+		last := first
+		if len(names) == 2 {
+			if last, ok = lookupWeekday(names[1]); !ok {
+				return NoWeekdays, errInvalidWeekdays
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			w = w.Add(day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return w, nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (w Weekdays) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (w *Weekdays) UnmarshalText(data []byte) (err error) {
+	*w, err = ParseWeekdays(string(data))
+	return
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The weekdays are a quoted string, for example "Mon-Fri".
+func (w Weekdays) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + w.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (w *Weekdays) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, nullText) {
+		*w = NoWeekdays
+		return nil
+	}
+	*w, err = ParseWeekdays(string(data))
+	return
+}
+
+// Scan implements the sql.Scanner interface.
+func (w *Weekdays) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		w1, err := ParseWeekdays(v)
+		if err != nil {
+			return err
+		}
+		*w = w1
+	case []byte:
+		w1, err := ParseWeekdays(string(v))
+		if err != nil {
+			return err
+		}
+		*w = w1
+	case int64:
+		if v < 0 || v > int64(AllWeekdays) {
+			return errors.New("cannot convert to civil.Weekdays")
+		}
+		*w = Weekdays(v)
+	case nil:
+		*w = NoWeekdays
+	default:
+		return errors.New("cannot convert to civil.Weekdays")
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (w Weekdays) Value() (driver.Value, error) {
+	return w.String(), nil
+}
+
+// In reports whether the day of the week on which d occurs is in w.
+func (d Date) In(w Weekdays) bool {
+	return w.Has(d.Weekday())
+}
+
+// Next returns the first date after d whose day of the week is in w.
+// If w is empty, Next returns the zero Date.
+func (d Date) Next(w Weekdays) Date {
+	if w.IsEmpty() {
+		return Date{}
+	}
+	for i := 1; ; i++ {
+		next := d.AddDate(0, 0, i)
+		if next.In(w) {
+			return next
+		}
+	}
+}
+
+func weekdayBit(day time.Weekday) Weekdays {
+	return 1 << (uint(day) % 7)
+}
+
+// isoWeekday returns the weekday for day n, where Monday is day zero.
+func isoWeekday(n int) time.Weekday {
+	return time.Weekday((n + 1) % 7)
+}
+
+func weekdayShortName(day time.Weekday) string {
+	return day.String()[:3]
+}
+
+func lookupWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day, names := range weekdayNames {
+		for _, n := range names {
+			if n == name {
+				return time.Weekday(day), true
+			}
+		}
+	}
+	return time.Sunday, false
+}
@@ -0,0 +1,138 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekdays(t *testing.T) {
+	assert := assert.New(t)
+	w := WeekdaysOf(time.Monday, time.Wednesday, time.Friday)
+	assert.True(w.Has(time.Monday))
+	assert.False(w.Has(time.Tuesday))
+	assert.Equal(3, w.Count())
+	assert.False(w.IsEmpty())
+	assert.True(NoWeekdays.IsEmpty())
+
+	w = w.Add(time.Sunday).Remove(time.Wednesday)
+	assert.Equal(3, w.Count())
+	assert.Equal([]time.Weekday{time.Monday, time.Friday, time.Sunday}, w.Days(time.Monday))
+	assert.Equal([]time.Weekday{time.Sunday, time.Monday, time.Friday}, w.Days(time.Sunday))
+	assert.Equal([]time.Weekday{time.Friday, time.Sunday, time.Monday}, w.Days(time.Friday))
+
+	assert.Equal(7, AllWeekdays.Count())
+	assert.Equal(5, MondayToFriday.Count())
+	assert.False(MondayToFriday.Has(time.Saturday))
+	assert.Equal(2, Weekend.Count())
+}
+
+func TestWeekdaysString(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Weekdays Weekdays
+		Text     string
+	}{
+		{NoWeekdays, ""},
+		{AllWeekdays, "Mon-Sun"},
+		{MondayToFriday, "Mon-Fri"},
+		{Weekend, "Sat,Sun"},
+		{MondayToFriday.Add(time.Sunday), "Mon-Fri,Sun"},
+		{WeekdaysOf(time.Monday, time.Wednesday, time.Friday), "Mon,Wed,Fri"},
+		{WeekdaysOf(time.Monday, time.Tuesday, time.Thursday), "Mon,Tue,Thu"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Text, tc.Weekdays.String())
+		w, err := ParseWeekdays(tc.Text)
+		assert.NoError(err, tc.Text)
+		assert.Equal(tc.Weekdays, w, tc.Text)
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Text     string
+		Expected Weekdays
+		Error    bool
+	}{
+		{Text: "Mon-Fri,Sun", Expected: MondayToFriday.Add(time.Sunday)},
+		{Text: "mo-fr", Expected: MondayToFriday},
+		{Text: "Saturday, Sunday", Expected: Weekend},
+		{Text: "Fri-Mon", Expected: WeekdaysOf(time.Friday, time.Saturday, time.Sunday, time.Monday)},
+		{Text: "Sun-Sat", Expected: AllWeekdays},
+		{Text: " Wed ", Expected: WeekdaysOf(time.Wednesday)},
+		{Text: `"Tue,Thu"`, Expected: WeekdaysOf(time.Tuesday, time.Thursday)},
+		{Text: "", Expected: NoWeekdays},
+		{Text: "Mon-Wed-Fri", Error: true},
+		{Text: "Mon,,Tue", Error: true},
+		{Text: "Funday", Error: true},
+		{Text: "Mon-", Error: true},
+	}
+
+	for _, tc := range testCases {
+		w, err := ParseWeekdays(tc.Text)
+		if tc.Error {
+			assert.Error(err, tc.Text)
+			continue
+		}
+		assert.NoError(err, tc.Text)
+		assert.Equal(tc.Expected, w, tc.Text)
+	}
+}
+
+func TestWeekdaysEncoding(t *testing.T) {
+	assert := assert.New(t)
+	type hours struct {
+		Days Weekdays `json:"days"`
+	}
+	data, err := json.Marshal(hours{Days: MondayToFriday})
+	assert.NoError(err)
+	assert.Equal(`{"days":"Mon-Fri"}`, string(data))
+
+	var h hours
+	assert.NoError(json.Unmarshal([]byte(`{"days":"Sat,Sun"}`), &h))
+	assert.Equal(Weekend, h.Days)
+	assert.NoError(json.Unmarshal([]byte(`{"days":null}`), &h))
+	assert.Equal(NoWeekdays, h.Days)
+	assert.Error(json.Unmarshal([]byte(`{"days":"xyz"}`), &h))
+
+	text, err := Weekend.MarshalText()
+	assert.NoError(err)
+	assert.Equal("Sat,Sun", string(text))
+	var w Weekdays
+	assert.NoError(w.UnmarshalText([]byte("Mon-Fri")))
+	assert.Equal(MondayToFriday, w)
+
+	v, err := MondayToFriday.Value()
+	assert.NoError(err)
+	assert.Equal("Mon-Fri", v)
+
+	for _, src := range []interface{}{"Mon-Fri", []byte("Mon-Fri"), int64(MondayToFriday)} {
+		var w Weekdays
+		assert.NoError(w.Scan(src))
+		assert.Equal(MondayToFriday, w)
+	}
+	assert.NoError(w.Scan(nil))
+	assert.Equal(NoWeekdays, w)
+	assert.Error(w.Scan(int64(256)))
+	assert.Error(w.Scan("Mon-"))
+	assert.Error(w.Scan([]byte("Mon-")))
+	assert.Error(w.Scan(1.5))
+}
+
+func TestDateInWeekdays(t *testing.T) {
+	assert := assert.New(t)
+	mwf := WeekdaysOf(time.Monday, time.Wednesday, time.Friday)
+	d := mustParseDate("2021-03-01") // Monday
+	assert.True(d.In(mwf))
+	assert.False(d.In(Weekend))
+	assert.Equal("2021-03-03", d.Next(mwf).String())
+	assert.Equal("2021-03-08", mustParseDate("2021-03-05").Next(mwf).String())
+	assert.Equal("2021-03-06", d.Next(Weekend).String())
+	assert.Equal("2021-03-08", d.Next(WeekdaysOf(time.Monday)).String())
+	assert.True(d.Next(NoWeekdays).IsZero())
+}
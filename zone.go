@@ -0,0 +1,168 @@
+package civil
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// locationCache avoids reading the time zone database every time
+// a zone name is parsed. Names that fail to load are cached too, because
+// names may come from untrusted input such as HTTP headers.
+var locationCache = struct {
+	sync.RWMutex
+	m      map[string]*time.Location
+	failed map[string]error
+}{
+	m:      make(map[string]*time.Location),
+	failed: make(map[string]error),
+}
+
+// maxFailedLocations limits the number of failed names that are cached.
+// The cache of failures is cleared when it is full.
+const maxFailedLocations = 1000
+
+// LoadLocation returns the location with the given IANA name, such as
+// "Australia/Sydney". It is the same as time.LoadLocation, except that
+// locations are cached, so the time zone database is only read once
+// for each name, and names that cannot be zone names are rejected
+// without reading it.
+func LoadLocation(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+	locationCache.RLock()
+	loc, ok := locationCache.m[name]
+	err := locationCache.failed[name]
+	locationCache.RUnlock()
+	if ok {
+		return loc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isZoneName(name) {
+		return nil, errors.New("unknown time zone " + name)
+	}
+
+	// the lock is not held while reading the time zone database
+	loc, err = time.LoadLocation(name)
+	locationCache.Lock()
+	defer locationCache.Unlock()
+	if err != nil {
+		if len(locationCache.failed) >= maxFailedLocations {
+			locationCache.failed = make(map[string]error)
+		}
+		locationCache.failed[name] = err
+		return nil, err
+	}
+	locationCache.m[name] = loc
+	return loc, nil
+}
+
+// isZoneName reports whether name has the form of an IANA time zone name,
+// such as "America/Argentina/Buenos_Aires" or "Etc/GMT+10".
+func isZoneName(name string) bool {
+	if len(name) > 64 || name[0] == '/' {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '/', c == '_', c == '-', c == '+':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// wallTime describes the offsets that apply to a civil date-time in a location.
+type wallTime struct {
+	// seconds is the civil date-time as seconds since 1970-01-01T00:00:00.
+	seconds int64
+
+	// valid contains the offsets for which the civil date-time exists.
+	// It has one entry normally, two entries when the date-time occurs
+	// twice in an overlap, and none when it falls in a gap. When there
+	// are two entries, the one giving the earlier instant is first.
+	valid []int
+
+	// before and after are the offsets in effect shortly before and
+	// shortly after the civil date-time.
+	before, after int
+}
+
+// lookupWallTime returns the offsets that apply to dt in loc.
+//
+// It assumes that there is at most one transition within a day of dt,
+// which is true of every zone in the IANA time zone database.
+func lookupWallTime(dt DateTime, loc *time.Location) wallTime {
+	w := wallTime{seconds: dt.Unix()}
+	_, w.before = time.Unix(w.seconds-secondsPerDay, 0).In(loc).Zone()
+	_, w.after = time.Unix(w.seconds+secondsPerDay, 0).In(loc).Zone()
+	offsets := []int{w.before}
+	if w.after != w.before {
+		offsets = append(offsets, w.after)
+		if w.after > w.before {
+			// larger offset gives the earlier instant
+			offsets[0], offsets[1] = w.after, w.before
+		}
+	}
+	for _, offset := range offsets {
+		if w.isValid(offset, loc) {
+			w.valid = append(w.valid, offset)
+		}
+	}
+	return w
+}
+
+// isValid reports whether the offset applies to the civil date-time in loc.
+func (w wallTime) isValid(offset int, loc *time.Location) bool {
+	_, actual := time.Unix(w.seconds-int64(offset), 0).In(loc).Zone()
+	return actual == offset
+}
+
+// instant returns the instant for the civil date-time using offset.
+func (w wallTime) instant(offset int, loc *time.Location) time.Time {
+	return time.Unix(w.seconds-int64(offset), 0).In(loc)
+}
+
+// earlier returns the earlier of the two candidate instants. In an overlap
+// this is the first occurrence of the civil date-time. In a gap it is
+// the instant that results from applying the offset in effect after the gap,
+// which gives a civil date-time earlier than dt.
+func (w wallTime) earlier(loc *time.Location) time.Time {
+	if len(w.valid) > 0 {
+		return w.instant(w.valid[0], loc)
+	}
+	return w.instant(maxInt(w.before, w.after), loc)
+}
+
+// later returns the later of the two candidate instants. In an overlap
+// this is the second occurrence of the civil date-time. In a gap it is
+// the instant that results from applying the offset in effect before the gap,
+// which gives a civil date-time later than dt.
+func (w wallTime) later(loc *time.Location) time.Time {
+	if len(w.valid) > 0 {
+		return w.instant(w.valid[len(w.valid)-1], loc)
+	}
+	return w.instant(minInt(w.before, w.after), loc)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
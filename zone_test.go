@@ -0,0 +1,36 @@
+package civil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	assert.Equal(t, "Australia/Sydney", loc.String())
+	loc2, err := LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	assert.True(t, loc == loc2, "cached")
+
+	loc, err = LoadLocation("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	for _, name := range []string{"Nowhere/Bogus", "../../etc/passwd", "/etc/localtime", "Australia/Sydney\x00", strings.Repeat("A", 100)} {
+		_, err := LoadLocation(name)
+		assert.Error(t, err, name)
+	}
+
+	// failures are cached
+	locationCache.RLock()
+	_, ok := locationCache.failed["Nowhere/Bogus"]
+	locationCache.RUnlock()
+	assert.True(t, ok)
+	_, err = LoadLocation("Nowhere/Bogus")
+	assert.Error(t, err)
+}
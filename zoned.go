@@ -0,0 +1,344 @@
+package civil
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidZonedDateTimeFormat = errors.New("invalid zoned date-time format")
+	errOffsetConflict             = errors.New("offset is not valid for the date-time in the time zone")
+)
+
+// OffsetConflict determines how ParseZonedDateTimeConflict resolves
+// a string whose offset does not agree with its time zone. This can
+// happen when a date-time is stored and the zone's rules change
+// before the date-time occurs. The options match those of the
+// JavaScript Temporal API.
+type OffsetConflict int
+
+const (
+	// OffsetReject returns an error if the offset is not valid
+	// for the date-time in the time zone.
+	OffsetReject OffsetConflict = iota
+
+	// OffsetPrefer uses the offset if it is valid for the date-time
+	// in the time zone, otherwise it ignores the offset.
+	OffsetPrefer
+
+	// OffsetUse uses the offset to determine the instant, and then
+	// converts the instant to the time zone. The date-time may change.
+	OffsetUse
+
+	// OffsetIgnore ignores the offset and determines the instant from
+	// the date-time and the time zone alone.
+	OffsetIgnore
+)
+
+// ZonedDateTime represents a civil date-time in a named time zone, as in
+// "2021-03-01T10:00:00+11:00[Australia/Sydney]". Keeping the zone name,
+// rather than just the offset, means the date-time can be reinterpreted
+// if the rules for the zone change.
+//
+// The offset is the one in effect in the zone at the date-time, and it
+// distinguishes between the two occurrences of a date-time that falls in
+// the overlap when clocks are turned back.
+//
+// When a ZonedDateTime is decoded from JSON, text or a database value,
+// conflicts are resolved with OffsetPrefer. A value stored before the rules
+// for its zone changed keeps its civil date-time, and its offset is updated.
+type ZonedDateTime struct {
+	dt     DateTime
+	offset int
+	loc    *time.Location
+}
+
+// ZonedDateTimeOf returns the ZonedDateTime corresponding to t in t's location.
+func ZonedDateTimeOf(t time.Time) ZonedDateTime {
+	t = t.Truncate(time.Second)
+	_, offset := t.Zone()
+	return ZonedDateTime{
+		dt:     DateTimeOf(t),
+		offset: offset,
+		loc:    t.Location(),
+	}
+}
+
+// ZonedDateTimeFor returns the ZonedDateTime for the civil date-time dt in loc.
+// If dt occurs twice in loc because clocks are turned back, the earlier
+// occurrence is used. If dt does not occur in loc because clocks are turned
+// forward, the date-time is moved forward by the length of the gap.
+// This is the same as DisambiguateCompatible.
+func ZonedDateTimeFor(dt DateTime, loc *time.Location) ZonedDateTime {
+	t, _ := dt.In(loc, DisambiguateCompatible)
+	return ZonedDateTimeOf(t)
+}
+
+// Local returns the civil date-time of z, without its offset or time zone.
+func (z ZonedDateTime) Local() DateTime {
+	return z.dt
+}
+
+// Offset returns the offset of z in seconds east of UTC.
+func (z ZonedDateTime) Offset() int {
+	return z.offset
+}
+
+// Location returns the time zone of z. The location of
+// the zero ZonedDateTime is UTC.
+func (z ZonedDateTime) Location() *time.Location {
+	if z.loc == nil {
+		return time.UTC
+	}
+	return z.loc
+}
+
+// Instant returns the instant in time that z represents, in z's location.
+func (z ZonedDateTime) Instant() time.Time {
+	return time.Unix(z.dt.Unix()-int64(z.offset), 0).In(z.Location())
+}
+
+// OffsetDateTime returns the date-time and offset of z without its time zone.
+func (z ZonedDateTime) OffsetDateTime() OffsetDateTime {
+	return OffsetDateTimeFor(z.dt, z.offset)
+}
+
+// Equal reports whether z and y have the same date-time, offset and time zone name.
+// To compare the instants they represent, use Instant().Equal.
+func (z ZonedDateTime) Equal(y ZonedDateTime) bool {
+	return z.dt.Equal(y.dt) && z.offset == y.offset && z.Location().String() == y.Location().String()
+}
+
+// IsZero reports whether z is the zero ZonedDateTime.
+func (z ZonedDateTime) IsZero() bool {
+	return z.dt.IsZero() && z.offset == 0 && (z.loc == nil || z.loc == time.UTC)
+}
+
+// String returns a string representation of z in the RFC 9557 format,
+// for example "2021-03-01T10:00:00+11:00[Australia/Sydney]".
+func (z ZonedDateTime) String() string {
+	return fmt.Sprintf("%s%s[%s]", z.dt, formatOffset(z.offset), z.Location())
+}
+
+// ParseZonedDateTime parses a date-time in the RFC 9557 format used by
+// the JavaScript Temporal API, for example
+// "2021-03-01T10:00:00+11:00[Australia/Sydney]". It returns an error if
+// the offset is not valid for the date-time in the time zone.
+// See ParseZonedDateTimeConflict for more details.
+func ParseZonedDateTime(s string) (ZonedDateTime, error) {
+	return ParseZonedDateTimeConflict(s, OffsetReject)
+}
+
+// ParseZonedDateTimeConflict parses a date-time in the RFC 9557 format, using
+// conflict to decide what to do if the offset is not valid for the date-time
+// in the time zone.
+//
+// The offset is optional. If it is missing, or if it is ignored, the
+// date-time is resolved in the same way as ZonedDateTimeFor. If the offset
+// is "Z", the string represents an exact instant, which is converted to
+// the time zone without conflict. Fractional seconds are discarded.
+// Annotations other than the time zone, such as "[u-ca=iso8601]",
+// are ignored unless they are marked critical with "!".
+func ParseZonedDateTimeConflict(s string, conflict OffsetConflict) (ZonedDateTime, error) {
+	s = strings.Trim(s, " \t\"'")
+	bracket := strings.IndexByte(s, '[')
+	if bracket < 0 {
+		return ZonedDateTime{}, errInvalidZonedDateTimeFormat
+	}
+	zone, err := parseAnnotations(s[bracket:])
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+	loc, err := LoadLocation(zone)
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+
+	s = s[:bracket]
+	var (
+		offset    int
+		hasOffset bool
+	)
+	if strings.HasSuffix(s, "Z") || strings.HasSuffix(s, "z") {
+		// an exact instant, which is converted to the zone
+		dt, err := parseZonedLocal(s[:len(s)-1])
+		if err != nil {
+			return ZonedDateTime{}, err
+		}
+		return ZonedDateTimeOf(time.Unix(dt.Unix(), 0).In(loc)), nil
+	}
+	if i := strings.LastIndexAny(s, "+-"); i > len("2006-01-02T") {
+		if offset, err = parseOffset(s[i:]); err != nil {
+			return ZonedDateTime{}, err
+		}
+		hasOffset = true
+		s = s[:i]
+	}
+
+	dt, err := parseZonedLocal(s)
+	if err != nil {
+		return ZonedDateTime{}, err
+	}
+	if !hasOffset || conflict == OffsetIgnore {
+		return ZonedDateTimeFor(dt, loc), nil
+	}
+
+	w := lookupWallTime(dt, loc)
+	for _, valid := range w.valid {
+		if valid == offset {
+			return ZonedDateTime{dt: dt, offset: offset, loc: loc}, nil
+		}
+	}
+	switch conflict {
+	case OffsetPrefer:
+		return ZonedDateTimeFor(dt, loc), nil
+	case OffsetUse:
+		return ZonedDateTimeOf(w.instant(offset, loc)), nil
+	}
+	return ZonedDateTime{}, errOffsetConflict
+}
+
+// parseZonedLocal parses the date-time part of a zoned date-time.
+func parseZonedLocal(s string) (DateTime, error) {
+	t, err := time.Parse("2006-01-02T15:04:05", s)
+	if err != nil {
+		return DateTime{}, errInvalidZonedDateTimeFormat
+	}
+	return DateTimeOf(t), nil
+}
+
+// parseAnnotations parses the bracketed suffixes of an RFC 9557
+// date-time and returns the time zone name.
+func parseAnnotations(s string) (zone string, err error) {
+	for s != "" {
+		end := strings.IndexByte(s, ']')
+		if s[0] != '[' || end < 0 {
+			return "", errInvalidZonedDateTimeFormat
+		}
+		annotation := s[1:end]
+		s = s[end+1:]
+		critical := strings.HasPrefix(annotation, "!")
+		annotation = strings.TrimPrefix(annotation, "!")
+		if strings.Contains(annotation, "=") {
+			if critical {
+				return "", fmt.Errorf("unsupported critical annotation: %s", annotation)
+			}
+			continue
+		}
+		if zone != "" || annotation == "" {
+			return "", errInvalidZonedDateTimeFormat
+		}
+		zone = annotation
+	}
+	if zone == "" {
+		return "", errInvalidZonedDateTimeFormat
+	}
+	return zone, nil
+}
+
+// parseOffset parses an offset of the form ±hh:mm or ±hh:mm:ss.
+func parseOffset(s string) (int, error) {
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	parts := strings.Split(s[1:], ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errInvalidZonedDateTimeFormat
+	}
+	seconds := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || len(part) != 2 || (i > 0 && n > 59) {
+			return 0, errInvalidZonedDateTimeFormat
+		}
+		seconds = seconds*60 + n
+	}
+	if len(parts) == 2 {
+		seconds *= 60
+	}
+	return sign * seconds, nil
+}
+
+// formatOffset formats an offset as ±hh:mm, or as ±hh:mm:ss if
+// the offset is not a whole number of minutes.
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	if offset%60 != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, offset/3600, offset/60%60, offset%60)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset/60%60)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The date-time is a quoted string in RFC 9557 format.
+func (z ZonedDateTime) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + z.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The date-time is expected to be a quoted string in RFC 9557 format.
+// An offset that is not valid for the date-time in the time zone is ignored.
+func (z *ZonedDateTime) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, nullText) {
+		*z = ZonedDateTime{}
+		return nil
+	}
+	*z, err = ParseZonedDateTimeConflict(string(data), OffsetPrefer)
+	return
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The date-time format is RFC 9557.
+func (z ZonedDateTime) MarshalText() ([]byte, error) {
+	return []byte(z.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The date-time is expected to be in RFC 9557 format.
+// An offset that is not valid for the date-time in the time zone is ignored.
+func (z *ZonedDateTime) UnmarshalText(data []byte) (err error) {
+	*z, err = ParseZonedDateTimeConflict(string(data), OffsetPrefer)
+	return
+}
+
+// Scan implements the sql.Scanner interface.
+// An offset that is not valid for the date-time in the time zone is ignored.
+func (z *ZonedDateTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		z1, err := ParseZonedDateTimeConflict(v, OffsetPrefer)
+		if err != nil {
+			return err
+		}
+		*z = z1
+	case []byte:
+		z1, err := ParseZonedDateTimeConflict(string(v), OffsetPrefer)
+		if err != nil {
+			return err
+		}
+		*z = z1
+	case time.Time:
+		*z = ZonedDateTimeOf(v)
+	case nil:
+		*z = ZonedDateTime{}
+	default:
+		return errors.New("cannot convert to civil.ZonedDateTime")
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface. The value is an
+// RFC 9557 string, so that the time zone name is stored.
+func (z ZonedDateTime) Value() (driver.Value, error) {
+	return z.String(), nil
+}
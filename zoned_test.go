@@ -0,0 +1,205 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return loc
+}
+
+func TestLookupWallTime(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	testCases := []struct {
+		DateTime string
+		Valid    []int
+	}{
+		{"2021-03-01T10:00", []int{11 * 3600}},
+		{"2021-07-01T10:00", []int{10 * 3600}},
+		// clocks turned back from 03:00 to 02:00
+		{"2021-04-04T02:30", []int{11 * 3600, 10 * 3600}},
+		{"2021-04-04T03:00", []int{10 * 3600}},
+		// clocks turned forward from 02:00 to 03:00
+		{"2021-10-03T02:30", nil},
+		{"2021-10-03T01:59:59", []int{10 * 3600}},
+		{"2021-10-03T03:00", []int{11 * 3600}},
+	}
+
+	for _, tc := range testCases {
+		w := lookupWallTime(mustParseDateTime(tc.DateTime), sydney)
+		assert.Equal(tc.Valid, w.valid, tc.DateTime)
+	}
+
+	gap := lookupWallTime(mustParseDateTime("2021-10-03T02:30"), sydney)
+	assert.Equal("2021-10-03T01:30:00", DateTimeOf(gap.earlier(sydney)).String())
+	assert.Equal("2021-10-03T03:30:00", DateTimeOf(gap.later(sydney)).String())
+
+	overlap := lookupWallTime(mustParseDateTime("2021-04-04T02:30"), sydney)
+	assert.Equal("2021-04-03T15:30:00Z", overlap.earlier(sydney).UTC().Format(time.RFC3339))
+	assert.Equal("2021-04-03T16:30:00Z", overlap.later(sydney).UTC().Format(time.RFC3339))
+}
+
+func TestZonedDateTime(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+
+	z := ZonedDateTimeFor(mustParseDateTime("2021-03-01T10:00"), sydney)
+	assert.Equal("2021-03-01T10:00:00+11:00[Australia/Sydney]", z.String())
+	assert.Equal("2021-03-01T10:00:00", z.Local().String())
+	assert.Equal(11*3600, z.Offset())
+	assert.Equal(sydney, z.Location())
+	assert.True(z.Instant().Equal(time.Date(2021, 2, 28, 23, 0, 0, 0, time.UTC)))
+	assert.Equal("2021-03-01T10:00:00+11:00", z.OffsetDateTime().String())
+
+	// gap: moved forward
+	z = ZonedDateTimeFor(mustParseDateTime("2021-10-03T02:30"), sydney)
+	assert.Equal("2021-10-03T03:30:00+11:00[Australia/Sydney]", z.String())
+
+	// overlap: earlier occurrence
+	z = ZonedDateTimeFor(mustParseDateTime("2021-04-04T02:30"), sydney)
+	assert.Equal("2021-04-04T02:30:00+11:00[Australia/Sydney]", z.String())
+
+	z = ZonedDateTimeOf(time.Date(2021, 4, 3, 16, 30, 0, 500, time.UTC).In(sydney))
+	assert.Equal("2021-04-04T02:30:00+10:00[Australia/Sydney]", z.String())
+
+	assert.True(ZonedDateTime{}.IsZero())
+	assert.Equal(time.UTC, ZonedDateTime{}.Location())
+	assert.Equal("0001-01-01T00:00:00+00:00[UTC]", ZonedDateTime{}.String())
+	assert.False(z.IsZero())
+}
+
+func TestParseZonedDateTime(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		Text     string
+		Conflict OffsetConflict
+		Expected string
+		Error    bool
+	}{
+		{Text: "2021-03-01T10:00:00+11:00[Australia/Sydney]", Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00[Australia/Sydney]", Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00.25+11:00[Australia/Sydney]", Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00+11:00[!Australia/Sydney][u-ca=iso8601]", Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00-05:00[America/New_York]", Expected: "2021-03-01T10:00:00-05:00[America/New_York]"},
+		{Text: "2021-03-01T10:00:00+00:00[UTC]", Expected: "2021-03-01T10:00:00+00:00[UTC]"},
+		{Text: "1890-01-01T10:00:00+10:04:52[Australia/Sydney]", Expected: "1890-01-01T10:00:00+10:04:52[Australia/Sydney]"},
+		{Text: "2021-03-01T00:00:00Z[Australia/Sydney]", Expected: "2021-03-01T11:00:00+11:00[Australia/Sydney]"},
+
+		// the offset selects the occurrence in an overlap
+		{Text: "2021-04-04T02:30:00+11:00[Australia/Sydney]", Expected: "2021-04-04T02:30:00+11:00[Australia/Sydney]"},
+		{Text: "2021-04-04T02:30:00+10:00[Australia/Sydney]", Expected: "2021-04-04T02:30:00+10:00[Australia/Sydney]"},
+		{Text: "2021-04-04T02:30:00+10:00[Australia/Sydney]", Conflict: OffsetIgnore, Expected: "2021-04-04T02:30:00+11:00[Australia/Sydney]"},
+
+		// conflicts
+		{Text: "2021-03-01T10:00:00+10:00[Australia/Sydney]", Error: true},
+		{Text: "2021-03-01T10:00:00+10:00[Australia/Sydney]", Conflict: OffsetPrefer, Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00+10:00[Australia/Sydney]", Conflict: OffsetUse, Expected: "2021-03-01T11:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-03-01T10:00:00+10:00[Australia/Sydney]", Conflict: OffsetIgnore, Expected: "2021-03-01T10:00:00+11:00[Australia/Sydney]"},
+		{Text: "2021-10-03T02:30:00+10:00[Australia/Sydney]", Error: true},
+		{Text: "2021-10-03T02:30:00+10:00[Australia/Sydney]", Conflict: OffsetUse, Expected: "2021-10-03T03:30:00+11:00[Australia/Sydney]"},
+
+		// invalid
+		{Text: "2021-03-01T10:00:00+11:00", Error: true},
+		{Text: "2021-03-01T10:00:00+11:00[Nowhere/Special]", Error: true},
+		{Text: "2021-03-01T10:00:00+11:00[]", Error: true},
+		{Text: "2021-03-01T10:00:00+11:00[Australia/Sydney", Error: true},
+		{Text: "2021-03-01T10:00:00+11:00[Australia/Sydney][Europe/Paris]", Error: true},
+		{Text: "2021-03-01T10:00:00+11:00[Australia/Sydney][!x-foo=bar]", Error: true},
+		{Text: "2021-03-01T10:00:00+1100[Australia/Sydney]", Error: true},
+		{Text: "2021-03-01T10:00:00+11:99[Australia/Sydney]", Error: true},
+		{Text: "2021-03-01[Australia/Sydney]", Error: true},
+		{Text: "xxxZ[Australia/Sydney]", Error: true},
+	}
+
+	for _, tc := range testCases {
+		z, err := ParseZonedDateTimeConflict(tc.Text, tc.Conflict)
+		if tc.Error {
+			assert.Error(err, tc.Text)
+			continue
+		}
+		if assert.NoError(err, tc.Text) {
+			assert.Equal(tc.Expected, z.String(), tc.Text)
+		}
+	}
+
+	_, err := ParseZonedDateTime("2021-03-01T10:00:00+10:00[Australia/Sydney]")
+	assert.Equal(errOffsetConflict, err)
+}
+
+func TestZonedDateTimeEncoding(t *testing.T) {
+	assert := assert.New(t)
+	sydney := mustLoadLocation("Australia/Sydney")
+	z := ZonedDateTimeOf(time.Date(2021, 4, 3, 16, 30, 0, 0, time.UTC).In(sydney))
+	text := "2021-04-04T02:30:00+10:00[Australia/Sydney]"
+
+	data, err := json.Marshal(z)
+	assert.NoError(err)
+	assert.Equal(`"`+text+`"`, string(data))
+	var z2 ZonedDateTime
+	assert.NoError(json.Unmarshal(data, &z2))
+	assert.True(z.Equal(z2))
+	assert.True(z.Instant().Equal(z2.Instant()))
+	assert.NoError(json.Unmarshal([]byte("null"), &z2))
+	assert.True(z2.IsZero())
+
+	b, err := z.MarshalText()
+	assert.NoError(err)
+	var z3 ZonedDateTime
+	assert.NoError(z3.UnmarshalText(b))
+	assert.True(z.Equal(z3))
+
+	v, err := z.Value()
+	assert.NoError(err)
+	assert.Equal(text, v)
+	for _, src := range []interface{}{text, []byte(text), z.Instant()} {
+		var z4 ZonedDateTime
+		assert.NoError(z4.Scan(src))
+		assert.True(z.Equal(z4), z4.String())
+	}
+	var z5 ZonedDateTime
+	assert.NoError(z5.Scan(nil))
+	assert.True(z5.IsZero())
+	assert.Error(z5.Scan("2021-03-01"))
+	assert.Error(z5.Scan([]byte("2021-03-01")))
+	assert.Error(z5.Scan(1))
+}
+
+func TestZonedDateTimeDecodeStaleOffset(t *testing.T) {
+	assert := assert.New(t)
+	// stored when the zone's rules gave an offset of +10:00 in March
+	stored := "2021-03-01T10:00:00+10:00[Australia/Sydney]"
+	expected := "2021-03-01T10:00:00+11:00[Australia/Sydney]"
+
+	var z ZonedDateTime
+	assert.NoError(json.Unmarshal([]byte(`"`+stored+`"`), &z))
+	assert.Equal(expected, z.String())
+	data, err := json.Marshal(z)
+	assert.NoError(err)
+	var z2 ZonedDateTime
+	assert.NoError(json.Unmarshal(data, &z2))
+	assert.True(z.Equal(z2))
+
+	var z3 ZonedDateTime
+	assert.NoError(z3.UnmarshalText([]byte(stored)))
+	assert.Equal(expected, z3.String())
+
+	for _, src := range []interface{}{stored, []byte(stored)} {
+		var z4 ZonedDateTime
+		assert.NoError(z4.Scan(src))
+		assert.Equal(expected, z4.String())
+		v, err := z4.Value()
+		assert.NoError(err)
+		var z5 ZonedDateTime
+		assert.NoError(z5.Scan(v))
+		assert.True(z4.Equal(z5))
+	}
+}